@@ -1,32 +1,114 @@
 package app
 
-import "github.com/eddy/pr-news/internal/github"
+import (
+	"time"
+
+	"github.com/eddy/pr-news/internal/doctor"
+	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/history"
+	"github.com/eddy/pr-news/internal/llm"
+	"github.com/eddy/pr-news/internal/retry"
+)
 
 // Messages for async operations
 
+// PreflightDoneMsg carries the startup dependency and auth checks.
+type PreflightDoneMsg struct {
+	Report doctor.Report
+}
+
 type ReposLoadedMsg struct {
-	Repos []string
-	Err   error
+	Repos     []string
+	Truncated bool // an owner had more than max_repos repos
+	Err       error
 }
 
+// RepoPRs is the fetch result for one selected repository.
+type RepoPRs struct {
+	Repo     string
+	PRs      []github.PR
+	Total    int       // PRs in the window before the path scope was applied
+	Merged   int       // PRs merged in the window, before the max_prs cap
+	Since    time.Time // start of the fetched window
+	FromMark bool      // window came from the repo's last-run mark
+	Err      error     // listing failed; the repo is left out of the digest
+}
+
+// PRsFetchedMsg carries the listed PRs of a search. Like every message a
+// search sends, it carries the Run that sent it so that the model can drop
+// those of a cancelled run.
 type PRsFetchedMsg struct {
-	PRs []github.PR
-	Err error
+	Run   int
+	Repos []RepoPRs // 선택 순서 유지
+	Err   error     // 모든 레포의 조회가 실패함
+}
+
+// PRProgressMsg reports that one more PR finished collecting.
+type PRProgressMsg struct {
+	Run     int
+	Current int
+	Total   int
+	Repo    string
+	PR      github.PR
 }
 
 type PRDataCollectedMsg struct {
-	Data      string
+	Run       int
+	Repos     []llm.RepoData // 레포별 수집 데이터 (PR 순서 유지)
 	Current   int
 	Total     int
-	StartDate string // 가장 오래된 PR 날짜
-	EndDate   string // 가장 최근 PR 날짜
+	StartDate string          // 가장 오래된 PR 날짜
+	EndDate   string          // 가장 최근 PR 날짜
+	Failures  github.Failures // 일부 데이터를 가져오지 못한 PR
+	Err       error           // 수집 단계 시간 초과
+}
+
+// RateLimitMsg reports that GitHub requests are paused by a rate limit.
+type RateLimitMsg struct {
+	Wait github.RateLimitWait
+}
+
+// RetryMsg reports that a failed GitHub or LLM call is about to be retried.
+type RetryMsg struct {
+	Run     int
+	Attempt retry.Attempt
+}
+
+// RateLimitTickMsg refreshes the rate limit countdown.
+type RateLimitTickMsg struct{}
+
+// PreflightMsg carries the API call estimate for the collection stage and
+// notes on quotas it would exhaust.
+type PreflightMsg struct {
+	Run      int
+	Estimate github.CallEstimate
+	Notes    []string
+}
+
+// SummaryChunkMsg carries a piece of the report while the LLM streams it.
+type SummaryChunkMsg struct {
+	Run  int
+	Text string
 }
 
 type SummaryDoneMsg struct {
+	Run     int
 	Summary string
 	Err     error
 }
 
+// HistoryLoadedMsg carries the saved reports for the History panel.
+type HistoryLoadedMsg struct {
+	Reports []history.Report
+	Err     error
+}
+
+// ReportSavedMsg reports the outcome of persisting a finished summary.
+type ReportSavedMsg struct{ Err error }
+
+// MarksSavedMsg reports the outcome of advancing the last-run marks.
+type MarksSavedMsg struct{ Err error }
+
 type ErrMsg struct{ Err error }
 
 // ClearCopyMsg clears the "Copied!" feedback after a delay
@@ -1,50 +1,143 @@
 package app
 
 import (
+	"context"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eddy/pr-news/internal/config"
+	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/history"
+	"github.com/eddy/pr-news/internal/llm"
 	"github.com/eddy/pr-news/internal/panel"
 )
 
 type AppState int
 
 const (
-	StateLoading AppState = iota
+	StatePreflight AppState = iota // 의존성·인증 점검
+	StateLoading
 	StateInput
 	StateFetching
 	StateSummarizing
 	StateDone
 	StateError
+	StateHistory
 )
 
 type Model struct {
-	State  AppState
-	Input  panel.InputPanel
-	Output panel.OutputPanel
+	State   AppState
+	Input   panel.InputPanel
+	Output  panel.OutputPanel
+	History panel.HistoryPanel
+
+	gh    github.Client
+	ghErr error // why gh could not be built; gh is nil then
+	cache *github.Cache
+	store *history.Store
+	marks *history.Marks
+
+	cfg        config.Config  // settings without a profile
+	run        config.Config  // settings for the current run (profile applied)
+	defaultLLM llm.Summarizer // built from cfg.LLM
+	llmErr     error          // why defaultLLM could not be built
+	llm        llm.Summarizer // used for the current run
+
+	// events carries progress messages from the running pipeline stage
+	events chan tea.Msg
+	// ctx is the current run's context; cancel aborts its requests and
+	// subprocesses (Esc while fetching or summarizing)
+	ctx    context.Context
+	cancel context.CancelFunc
+	runID  int // the current search; async messages carry the ID of theirs
+	// notices carries RateLimitMsg and RetryMsg from the GitHub and LLM
+	// clients at any stage
+	notices chan tea.Msg
+	paused  github.RateLimitWait // 가장 늦게 끝나는 대기
 
 	// collected data
-	prData    string
+	repos     []string  // 선택한 레포 (여러 개면 digest)
+	fetched   []RepoPRs // PR이 있는 레포만
 	prCount   int
-	repo      string
-	dateRange string // PR 기간 (예: "2026-01-26 ~ 2026-02-02")
+	branch    string
+	dateRange string   // PR 기간 (예: "2026-01-26 ~ 2026-02-02")
+	warnings  []string // 보고서에 남길 경고 (예: PR 목록 잘림)
 
 	width  int
 	height int
 }
 
-func NewModel() Model {
+// Options wires the backends used by the TUI.
+type Options struct {
+	GitHub     github.Client
+	GitHubErr  error         // why GitHub could not be built; shown by the preflight checklist
+	Cache      *github.Cache // nil disables the PR data cache
+	History    *history.Store
+	Marks      *history.Marks
+	Summarizer llm.Summarizer // built from Config.LLM
+	LLMErr     error          // why Summarizer could not be built; a profile may not need it
+	Config     config.Config
+}
+
+func NewModel(opts Options) Model {
 	o := panel.NewOutputPanel()
-	o.State = panel.OutputLoading
+	o.State = panel.OutputPreflight
+	in := panel.NewInputPanel(panel.Profile{Days: opts.Config.Days, Branch: opts.Config.Branch, Paths: opts.Config.Limits.Scope.String()})
+	in.Profiles = profiles(opts.Config)
+	notices := make(chan tea.Msg, 8)
+	if opts.GitHub != nil {
+		opts.GitHub.OnRateLimit(func(w github.RateLimitWait) {
+			notify(notices, RateLimitMsg{Wait: w})
+		})
+	}
 	return Model{
-		State:  StateLoading,
-		Input:  panel.NewInputPanel(),
-		Output: o,
+		State:      StatePreflight,
+		Input:      in,
+		Output:     o,
+		History:    panel.NewHistoryPanel(),
+		gh:         opts.GitHub,
+		ghErr:      opts.GitHubErr,
+		cache:      opts.Cache,
+		store:      opts.History,
+		marks:      opts.Marks,
+		cfg:        opts.Config,
+		run:        opts.Config,
+		defaultLLM: opts.Summarizer,
+		llmErr:     opts.LLMErr,
+		llm:        opts.Summarizer,
+		notices:    notices,
+	}
+}
+
+// notify queues msg on notices without blocking the caller.
+func notify(notices chan<- tea.Msg, msg tea.Msg) {
+	select {
+	case notices <- msg:
+	default: // 이미 대기 중인 알림이 쌓여 있음
+	}
+}
+
+// profiles describes cfg's profiles for the input panel's picker.
+func profiles(cfg config.Config) []panel.Profile {
+	var out []panel.Profile
+	for _, p := range cfg.Profiles {
+		c, err := cfg.WithProfile(p.Name)
+		if err != nil {
+			continue
+		}
+		pp := panel.Profile{Name: p.Name, Days: c.Days, Branch: c.Branch, Paths: c.Limits.Scope.String(), Match: p.Matches}
+		if p.Concrete() {
+			pp.Repos = p.Repos
+		}
+		out = append(out, pp)
 	}
+	return out
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.Input.Init(),
 		m.Output.Init(),
-		loadReposCmd(),
+		preflightCmd(m.checks()),
+		waitForEvent(m.notices),
 	)
 }
@@ -1,16 +1,24 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eddy/pr-news/internal/doctor"
 	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/history"
 	"github.com/eddy/pr-news/internal/llm"
 	"github.com/eddy/pr-news/internal/panel"
+	"github.com/eddy/pr-news/internal/proc"
+	"github.com/eddy/pr-news/internal/retry"
+	"github.com/eddy/pr-news/internal/style"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -23,32 +31,64 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Input.Width = inputW
 		m.Input.Height = msg.Height - 2
 		m.Output.SetSize(outputW, msg.Height-2)
+		m.History.Width = inputW
+		m.History.Height = msg.Height - 2
 		return m, nil
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
+			m.cancelRun()
 			return m, tea.Quit
+		case "esc":
+			if m.State == StateFetching || m.State == StateSummarizing {
+				m.cancelRun()
+				m.State = StateInput
+				m.Output.State = panel.OutputIdle
+				m.Output.Hint = "Cancelled. Press Enter to search again."
+				m.Output.Notice = ""
+				m.Output.Retry = ""
+				m.Output.Warnings = nil
+				m.Output.ClearLog()
+				return m, nil
+			}
 		case "q":
-			if m.State == StateDone || m.State == StateError {
+			if m.State == StateDone || m.State == StateError || m.State == StatePreflight {
 				return m, tea.Quit
 			}
 		case "r":
+			if m.State == StatePreflight && m.Output.Checks != nil {
+				m.Output.Checks = nil
+				return m, preflightCmd(m.checks())
+			}
 			if m.State == StateDone || m.State == StateError {
 				m.State = StateInput
 				m.Output.State = panel.OutputIdle
-				m.prData = ""
+				m.fetched = nil
+				m.Output.ClearLog()
 				return m, nil
 			}
+		case "enter":
+			if m.State == StatePreflight && m.Output.Checks != nil {
+				return m, m.loadRepos() // 실패를 무시하고 계속
+			}
 		case "c":
-			if m.State == StateDone && m.Output.RawContent != "" {
-				cmd := exec.Command("pbcopy")
+			if (m.State == StateDone || m.State == StateHistory) && m.Output.State == panel.OutputDone && m.Output.RawContent != "" {
+				cmd := exec.Command(doctor.ClipboardCommand)
 				cmd.Stdin = strings.NewReader(m.Output.RawContent)
 				if err := cmd.Run(); err == nil {
 					m.Output.CopyMsg = "Copied!"
 					return m, clearCopyMsgAfter(2 * time.Second)
 				}
 			}
+		case "ctrl+o":
+			if m.State == StateInput {
+				return m, m.openHistory()
+			}
+		case "h":
+			if m.State == StateDone || m.State == StateError {
+				return m, m.openHistory()
+			}
 		}
 
 	case panel.StartSearchMsg:
@@ -56,59 +96,248 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.startFetch()
 		}
 
+	case HistoryLoadedMsg:
+		if msg.Err != nil {
+			m.Output.Hint = style.ErrorText.Render("Error: " + msg.Err.Error())
+			return m, nil
+		}
+		m.History.SetReports(msg.Reports)
+		return m, nil
+
+	case panel.OpenReportMsg:
+		m.showReport(msg.Report.Markdown)
+		return m, nil
+
+	case panel.DiffReportsMsg:
+		m.showReport(fmt.Sprintf("# Diff\n\n- old: %s\n- new: %s\n\n```diff\n%s\n```",
+			msg.Old.Title(), msg.New.Title(), history.Diff(msg.Old.Markdown, msg.New.Markdown)))
+		return m, nil
+
+	case panel.DeleteReportMsg:
+		m.Output.State = panel.OutputIdle
+		return m, deleteReportCmd(m.store, msg.ID)
+
+	case panel.CloseHistoryMsg:
+		m.State = StateInput
+		m.Output.State = panel.OutputIdle
+		m.Output.Hint = ""
+		m.Output.Keys = ""
+		return m, nil
+
+	case ReportSavedMsg:
+		if msg.Err != nil {
+			m.Output.CopyMsg = "History save failed"
+			return m, clearCopyMsgAfter(3 * time.Second)
+		}
+		return m, nil
+
+	case MarksSavedMsg:
+		if msg.Err != nil {
+			m.Output.CopyMsg = "Last-run mark save failed"
+			return m, clearCopyMsgAfter(3 * time.Second)
+		}
+		return m, nil
+
 	case ClearCopyMsg:
 		m.Output.CopyMsg = ""
 		return m, nil
 
+	case PreflightDoneMsg:
+		if m.State != StatePreflight {
+			return m, nil
+		}
+		if msg.Report.Failed() {
+			m.Output.Checks = msg.Report
+			return m, nil
+		}
+		m.Output.Warnings = msg.Report.Warnings()
+		return m, m.loadRepos()
+
 	case ReposLoadedMsg:
 		if msg.Err != nil {
-			m.State = StateError
-			m.Output.State = panel.OutputError
-			m.Output.Error = msg.Err.Error()
+			m.showError(msg.Err)
 			return m, nil
 		}
 		m.Input.SetRepos(msg.Repos)
 		m.State = StateInput
 		m.Output.State = panel.OutputIdle
+		if msg.Truncated {
+			m.Output.Warnings = append(m.Output.Warnings, fmt.Sprintf("Repo list truncated to %d most recently pushed per owner (max_repos)", m.cfg.MaxRepos))
+		}
 		return m, nil
 
 	case PRsFetchedMsg:
+		if m.stale(msg.Run, StateFetching) {
+			return m, nil // 취소된 실행의 결과
+		}
 		if msg.Err != nil {
-			m.State = StateError
-			m.Output.State = panel.OutputError
-			m.Output.Error = msg.Err.Error()
+			m.showError(msg.Err)
 			return m, nil
 		}
-		m.prCount = len(msg.PRs)
+		m.fetched, m.prCount = nil, 0
+		for _, r := range msg.Repos {
+			if r.Err != nil {
+				m.Output.Warnings = append(m.Output.Warnings, fmt.Sprintf("%s: skipped, listing PRs failed: %v", r.Repo, r.Err))
+				m.warnings = append(m.warnings, github.SkippedNote(r.Repo, r.Err))
+				continue
+			}
+			if w := github.TruncationNote(r.Repo, r.Merged, m.run.MaxPRs); w != "" {
+				kept := github.PRLimit(m.run.MaxPRs)
+				m.Output.Warnings = append(m.Output.Warnings, fmt.Sprintf("%s: %d PRs truncated (%d most recently merged of %d kept, max_prs)", r.Repo, r.Merged-kept, kept, r.Merged))
+				m.warnings = append(m.warnings, w)
+			}
+			if scope := m.run.Limits.ScopeLabel(); scope != "" {
+				m.Output.AddLog(fmt.Sprintf("%s: %d of %d PRs touch %s", r.Repo, len(r.PRs), r.Total, scope))
+			}
+			if len(r.PRs) == 0 {
+				if len(msg.Repos) > 1 {
+					m.Output.AddLog(fmt.Sprintf("%s: no merged PRs", r.Repo))
+				}
+				continue
+			}
+			m.fetched = append(m.fetched, r)
+			m.prCount += len(r.PRs)
+		}
 		if m.prCount == 0 {
-			m.State = StateError
-			m.Output.State = panel.OutputError
-			m.Output.Error = "No merged PRs found"
+			m.showError(errors.New("No merged PRs found"))
+			return m, nil
+		}
+		if len(m.fetched) > 1 {
+			m.Output.Status = fmt.Sprintf("Collecting data from %d PRs in %d repos...", m.prCount, len(m.fetched))
+		} else {
+			m.Output.Status = fmt.Sprintf("Collecting data from %d PRs...", m.prCount)
+		}
+		m.Output.Progress = fmt.Sprintf("0/%d PRs collected", m.prCount)
+		m.Output.Retry = ""
+		m.events = make(chan tea.Msg)
+		return m, tea.Batch(
+			collectPRDataCmd(m.ctx, m.runID, m.collector(), m.fetched, m.run.Timeouts.Collect, m.events),
+			waitForEvent(m.events),
+		)
+
+	case PreflightMsg:
+		if m.stale(msg.Run, StateFetching) {
+			return m, nil
+		}
+		m.Output.AddLog("Estimated " + msg.Estimate.String())
+		m.Output.Warnings = append(m.Output.Warnings, msg.Notes...)
+		return m, waitForEvent(m.events)
+
+	case RateLimitMsg:
+		m.Output.AddLog(msg.Wait.String())
+		m.Output.Notice = msg.Wait.String()
+		// 동시에 여러 요청이 멈추면 가장 늦은 재개 시각까지 한 번만 카운트다운한다
+		ticking := time.Now().Before(m.paused.Until)
+		if msg.Wait.Until.After(m.paused.Until) {
+			m.paused = msg.Wait
+		}
+		if ticking {
+			return m, waitForEvent(m.notices)
+		}
+		return m, tea.Batch(waitForEvent(m.notices), rateLimitTick())
+
+	case RetryMsg:
+		if msg.Run == m.runID && (m.State == StateFetching || m.State == StateSummarizing) {
+			a := msg.Attempt
+			m.Output.AddLog(a.String())
+			m.Output.Retry = fmt.Sprintf("retrying %s (%d/%d)", a.What, a.N, a.Of)
+		}
+		return m, waitForEvent(m.notices)
+
+	case RateLimitTickMsg:
+		if time.Now().After(m.paused.Until) {
+			m.Output.Notice = ""
 			return m, nil
 		}
-		m.Output.Status = fmt.Sprintf("Collecting data from %d PRs...", m.prCount)
-		return m, collectPRDataCmd(m.repo, msg.PRs)
+		m.Output.Notice = m.paused.String()
+		return m, rateLimitTick()
+
+	case PRProgressMsg:
+		if m.stale(msg.Run, StateFetching) {
+			return m, nil // 수집 완료 후 늦게 도착한 진행 메시지
+		}
+		m.Output.Progress = fmt.Sprintf("%d/%d PRs collected", msg.Current, msg.Total)
+		if len(m.fetched) > 1 {
+			m.Output.AddLog(fmt.Sprintf("[%d/%d] %s#%d: %s", msg.Current, msg.Total, msg.Repo, msg.PR.Number, msg.PR.Title))
+		} else {
+			m.Output.AddLog(fmt.Sprintf("[%d/%d] PR #%d: %s", msg.Current, msg.Total, msg.PR.Number, msg.PR.Title))
+		}
+		return m, waitForEvent(m.events)
 
 	case PRDataCollectedMsg:
-		m.prData = msg.Data
+		if m.stale(msg.Run, StateFetching) {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.showError(msg.Err)
+			return m, nil
+		}
+		if len(msg.Repos) == 0 {
+			m.showError(errors.New("No merged PRs left after skipping bot PRs"))
+			return m, nil
+		}
+		for _, f := range msg.Failures {
+			m.Output.AddLog("Missing " + f.Error())
+		}
+		for _, r := range m.fetched {
+			failed := msg.Failures.For(r.Repo)
+			m.Output.Warnings = append(m.Output.Warnings, failed.Summary(r.Repo)...)
+			if note := failed.Note(r.Repo); note != "" {
+				m.warnings = append(m.warnings, note)
+			}
+		}
 		m.dateRange = fmt.Sprintf("%s ~ %s", msg.StartDate, msg.EndDate)
 		m.State = StateSummarizing
 		m.Output.State = panel.OutputSummarizing
-		m.Output.Status = "Claude is analyzing..."
+		m.Output.Status = fmt.Sprintf("%s is analyzing...", m.llm.Name())
 		m.Output.Progress = fmt.Sprintf("%d PRs collected (%s)", msg.Total, m.dateRange)
-		return m, summarizeCmd(m.prData, m.repo, m.prCount, m.dateRange)
+		m.Output.Retry = ""
+		m.Output.ResetStream()
+		m.events = make(chan tea.Msg)
+		return m, tea.Batch(
+			summarizeCmd(m.ctx, m.runID, m.pipeline(), msg.Repos, m.dateRange, m.run.Timeouts.Summarize, m.events),
+			waitForEvent(m.events),
+		)
+
+	case SummaryChunkMsg:
+		if m.stale(msg.Run, StateSummarizing) {
+			return m, nil // 완료 후 늦게 도착한 청크
+		}
+		return m, tea.Batch(m.Output.AppendStream(msg.Text), waitForEvent(m.events))
 
 	case SummaryDoneMsg:
+		if m.stale(msg.Run, StateSummarizing) {
+			return m, nil
+		}
+		m.cancelRun()
 		if msg.Err != nil {
-			m.State = StateError
-			m.Output.State = panel.OutputError
-			m.Output.Error = msg.Err.Error()
+			m.showError(msg.Err)
 			return m, nil
 		}
 		m.State = StateDone
 		m.Output.State = panel.OutputDone
 		m.Output.SetContent(msg.Summary)
-		return m, nil
+		var numbers []int
+		for _, r := range m.fetched {
+			for _, pr := range r.PRs {
+				numbers = append(numbers, pr.Number)
+			}
+		}
+		return m, tea.Batch(
+			saveReportCmd(m.store, history.Report{
+				Repo:      strings.Join(m.repos, ", "),
+				Branch:    m.branch,
+				DateRange: m.dateRange,
+				PRNumbers: numbers,
+				Model:     m.llm.Name(),
+				Markdown:  msg.Summary,
+			}),
+			advanceMarksCmd(m.marks, m.fetched),
+		)
+	}
+
+	if m.State == StateHistory {
+		return m.updateHistory(msg)
 	}
 
 	// Delegate to panels
@@ -126,72 +355,382 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// showError switches to the error view for err, with its remediation
+// hint when it came from a CLI failure the hint covers.
+func (m *Model) showError(err error) {
+	m.State = StateError
+	m.Output.State = panel.OutputError
+	m.Output.Error = err.Error()
+	m.Output.Fix = proc.Hint(err)
+}
+
 func (m *Model) startFetch() tea.Cmd {
-	repo := m.Input.SelectedRepo()
-	if repo == "" {
+	repos := m.Input.SelectedRepos()
+	if len(repos) == 0 {
+		return nil
+	}
+	if err := m.applyProfile(m.Input.Profile()); err != nil {
+		m.showError(err)
 		return nil
 	}
-	m.repo = repo
+	m.repos = repos
+	m.cancelRun()
+	m.runID++
+	var ctx context.Context
+	ctx, m.cancel = context.WithCancel(context.Background())
+	notices, run := m.notices, m.runID
+	m.ctx = retry.WithNotify(ctx, func(a retry.Attempt) { notify(notices, RetryMsg{Run: run, Attempt: a}) })
+	target := repos[0]
+	if len(repos) > 1 {
+		target = fmt.Sprintf("%d repos", len(repos))
+	}
 	m.State = StateFetching
 	m.Output.State = panel.OutputFetching
-	m.Output.Status = fmt.Sprintf("Fetching merged PRs from %s...", repo)
+	m.Output.Status = fmt.Sprintf("Fetching merged PRs from %s...", target)
+	m.Output.Progress = ""
+	m.Output.Retry = ""
+	m.Output.Warnings = nil
+	m.warnings = nil
+	m.Output.ClearLog()
 
 	daysStr := m.Input.Days.Value()
 	days, err := strconv.Atoi(daysStr)
 	if err != nil || days <= 0 {
-		days = 7
+		days = m.run.Days
 	}
 	branch := strings.TrimSpace(m.Input.Branch.Value())
+	m.branch = branch
+	m.run.Limits.Scope = github.ParseScope(m.Input.Paths.Value())
 
-	return fetchPRsCmd(repo, days, branch)
+	if m.Input.SinceLast {
+		m.Output.Status = fmt.Sprintf("Fetching PRs merged in %s since last run...", target)
+	}
+	if m.run.Profile != "" {
+		m.Output.AddLog("Profile: " + m.run.Profile)
+	}
+	if scope := m.run.Limits.ScopeLabel(); scope != "" {
+		m.Output.AddLog("Scope: " + scope)
+	}
+	return fetchPRsCmd(m.ctx, m.runID, m.collector(), m.marks, repos, days, m.Input.SinceLast, branch, m.run.MaxPRs, m.run.Timeouts.Fetch)
+}
+
+// updateHistory routes input while the History panel is open: paging keys
+// scroll the opened report, everything else drives the list.
+func (m Model) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if km, ok := msg.(tea.KeyMsg); ok {
+		switch km.String() {
+		case "pgup", "pgdown", "ctrl+u", "ctrl+d":
+			m.Output, cmd = m.Output.Update(msg)
+		default:
+			m.History, cmd = m.History.Update(msg)
+		}
+		return m, cmd
+	}
+	m.Output, cmd = m.Output.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) openHistory() tea.Cmd {
+	m.State = StateHistory
+	m.Output.State = panel.OutputIdle
+	m.Output.Hint = "Select a saved report and press Enter."
+	m.Output.Keys = "PgUp/PgDn scroll  c copy"
+	return loadHistoryCmd(m.store)
+}
+
+func (m *Model) showReport(md string) {
+	m.Output.State = panel.OutputDone
+	m.Output.SetContent(md)
+}
+
+// applyProfile switches the run settings to the named profile ("" for
+// none), building a new Summarizer if the profile changes the LLM. The
+// default summarizer's construction error only fails runs that use it.
+func (m *Model) applyProfile(name string) error {
+	run, err := m.cfg.WithProfile(name)
+	if err != nil {
+		return err
+	}
+	s := m.defaultLLM
+	if run.LLM != m.cfg.LLM {
+		if s, err = llm.New(run.LLM); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	} else if m.llmErr != nil {
+		return m.llmErr
+	}
+	m.run, m.llm = run, s
+	return nil
+}
+
+// stale reports whether a message of run arrived after that run was
+// cancelled or replaced, or outside the state that expects it.
+func (m *Model) stale(run int, state AppState) bool {
+	return run != m.runID || m.State != state
+}
+
+// cancelRun aborts the current run, if any.
+func (m *Model) cancelRun() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
 }
 
-func loadReposCmd() tea.Cmd {
+func (m *Model) collector() *github.Collector {
+	return &github.Collector{Client: m.gh, Workers: m.run.Workers, Cache: m.cache, Limits: m.run.Limits}
+}
+
+func (m *Model) pipeline() llm.Pipeline {
+	return llm.Pipeline{Summarizer: m.llm, TokenBudget: m.run.TokenBudget, Prompt: m.run.Prompt, Scope: m.run.Limits.ScopeLabel(), Warnings: m.warnings, Retry: m.run.LLM.RetryPolicy()}
+}
+
+// loadRepos leaves the preflight checklist and loads the repo picker.
+func (m *Model) loadRepos() tea.Cmd {
+	if m.gh == nil {
+		m.showError(m.ghErr)
+		return nil
+	}
+	m.State = StateLoading
+	m.Output.State = panel.OutputLoading
+	return loadReposCmd(m.gh, m.cfg.MaxRepos)
+}
+
+// checks describes the GitHub client and summarizer for the preflight
+// checklist, including why either could not be built.
+func (m *Model) checks() doctor.Options {
+	return doctor.Options{GitHub: m.gh, GitHubErr: m.ghErr, Summarizer: m.defaultLLM, LLMErr: m.llmErr}
+}
+
+// preflightCmd runs the startup checks.
+func preflightCmd(opts doctor.Options) tea.Cmd {
 	return func() tea.Msg {
-		repos, err := github.ListRepos(30)
-		return ReposLoadedMsg{Repos: repos, Err: err}
+		return PreflightDoneMsg{Report: doctor.Run(context.Background(), opts)}
 	}
 }
 
-func fetchPRsCmd(repo string, days int, branch string) tea.Cmd {
+func loadReposCmd(gh github.Client, maxRepos int) tea.Cmd {
 	return func() tea.Msg {
-		prs, err := github.ListMergedPRs(repo, days, branch)
-		return PRsFetchedMsg{PRs: prs, Err: err}
+		repos, truncated, err := gh.ListRepos(context.Background(), maxRepos)
+		return ReposLoadedMsg{Repos: repos, Truncated: truncated, Err: err}
 	}
 }
 
-func collectPRDataCmd(repo string, prs []github.PR) tea.Cmd {
+// fetchPRsCmd lists merged PRs of each repo in parallel for the last days,
+// or, with sinceLast, since the repo's last-run mark (falling back to days
+// on the first run), keeping those in the collector's path scope, within
+// timeout overall. A repo that fails is reported in its RepoPRs; the
+// message carries an error only when every repo failed. It returns no
+// message once ctx is cancelled.
+func fetchPRsCmd(ctx context.Context, run int, c *github.Collector, marks *history.Marks, repos []string, days int, sinceLast bool, branch string, maxPRs int, timeout time.Duration) tea.Cmd {
 	return func() tea.Msg {
-		var b strings.Builder
-		var startDate, endDate time.Time
+		stage, cancel := retry.WithTimeout(ctx, timeout)
+		defer cancel()
+		results := make([]RepoPRs, len(repos))
+		var wg sync.WaitGroup
+		for i, repo := range repos {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				since := github.DaysAgo(days)
+				var mark *history.Mark
+				if sinceLast && marks != nil {
+					if mk, ok := marks.Get(repo); ok {
+						since, mark = mk.MergedAt, &mk
+					}
+				}
+				prs, merged, err := c.Client.ListMergedPRs(stage, repo, since, branch, maxPRs)
+				if err == nil && mark != nil {
+					prs = mark.Unseen(prs)
+				}
+				total := len(prs)
+				if err == nil {
+					prs, err = c.InScope(stage, repo, prs)
+				}
+				if err != nil {
+					results[i] = RepoPRs{Repo: repo, Err: retry.StageError(stage, "fetching PRs", timeout, err)}
+					return
+				}
+				results[i] = RepoPRs{Repo: repo, PRs: prs, Total: total, Merged: merged, Since: since, FromMark: mark != nil}
+			}()
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return nil
+		}
+		var errs []error
+		for _, r := range results {
+			if r.Err == nil {
+				return PRsFetchedMsg{Run: run, Repos: results}
+			}
+			if len(repos) > 1 {
+				errs = append(errs, fmt.Errorf("%s: %w", r.Repo, r.Err))
+			} else {
+				errs = append(errs, r.Err)
+			}
+		}
+		return PRsFetchedMsg{Run: run, Repos: results, Err: errors.Join(errs...)}
+	}
+}
 
-		for i, pr := range prs {
-			// 날짜 범위 계산
-			if i == 0 || pr.MergedAt.Before(startDate) {
-				startDate = pr.MergedAt
+// advanceMarksCmd moves each repo's last-run mark to its newest summarized PR.
+func advanceMarksCmd(marks *history.Marks, fetched []RepoPRs) tea.Cmd {
+	return func() tea.Msg {
+		if marks == nil {
+			return nil
+		}
+		var errs []error
+		for _, r := range fetched {
+			errs = append(errs, marks.Advance(r.Repo, history.MarkFor(r.PRs)))
+		}
+		return MarksSavedMsg{Err: errors.Join(errs...)}
+	}
+}
+
+// collectPRDataCmd collects the PRs of each repo concurrently, sending a
+// PRProgressMsg on events as each one finishes, within timeout overall.
+// events is closed when collection is done.
+func collectPRDataCmd(ctx context.Context, run int, c *github.Collector, fetched []RepoPRs, timeout time.Duration, events chan<- tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		defer close(events)
+		send := sender(ctx, events)
+		stage, cancel := retry.WithTimeout(ctx, timeout)
+		defer cancel()
+		var all []github.PR
+		var estimate github.CallEstimate
+		for _, r := range fetched {
+			all = append(all, r.PRs...)
+			estimate = estimate.Add(c.EstimateCalls(r.Repo, r.PRs))
+		}
+		send(PreflightMsg{Run: run, Estimate: estimate, Notes: github.Preflight(stage, c.Client, estimate)})
+
+		data := make([]llm.RepoData, 0, len(fetched))
+		var failures github.Failures
+		offset := 0
+		for _, r := range fetched {
+			chunks, failed, err := c.Collect(stage, r.Repo, r.PRs, func(done int, pr github.PR) {
+				send(PRProgressMsg{Run: run, Current: offset + done, Total: len(all), Repo: r.Repo, PR: pr})
+			})
+			if ctx.Err() != nil {
+				return nil // 취소됨
 			}
-			if i == 0 || pr.MergedAt.After(endDate) {
-				endDate = pr.MergedAt
+			if err != nil {
+				return PRDataCollectedMsg{Run: run, Err: retry.StageError(stage, "collecting PR data", timeout, err)}
+			}
+			offset += len(r.PRs)
+			failures = append(failures, failed...)
+			if len(chunks) == 0 {
+				continue // 봇 PR만 있어 모두 제외됨
 			}
 
-			data := github.CollectPRData(repo, pr)
-			b.WriteString(data)
-			b.WriteString("\n---\n")
+			start, end := github.DateRange(r.PRs)
+			rd := llm.RepoData{
+				Repo:      r.Repo,
+				Chunks:    chunks,
+				DateRange: fmt.Sprintf("%s ~ %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+			}
+			if c.Limits.OwnerMode == github.OwnerModeGroup {
+				rd.Appendix, _ = c.OwnerIndex(stage, r.Repo, r.PRs) // 오너 목록은 부가 정보
+			}
+			data = append(data, rd)
 		}
+
+		// 날짜 범위 계산
+		startDate, endDate := github.DateRange(all)
 		return PRDataCollectedMsg{
-			Data:      b.String(),
-			Current:   len(prs),
-			Total:     len(prs),
+			Run:       run,
+			Repos:     data,
+			Failures:  failures,
+			Current:   len(all),
+			Total:     len(all),
 			StartDate: startDate.Format("2006-01-02"),
 			EndDate:   endDate.Format("2006-01-02"),
 		}
 	}
 }
 
-func summarizeCmd(prData, repo string, count int, dateRange string) tea.Cmd {
+// waitForEvent delivers the next message from a running stage; it yields nil
+// once the channel is closed.
+func waitForEvent(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-events
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// sender returns a send on events that gives up once ctx is cancelled,
+// when nothing reads events any more.
+func sender(ctx context.Context, events chan<- tea.Msg) func(tea.Msg) {
+	return func(msg tea.Msg) {
+		select {
+		case events <- msg:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// rateLimitTick schedules the next rate limit countdown refresh.
+func rateLimitTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return RateLimitTickMsg{} })
+}
+
+// summarizeCmd runs the LLM pipeline, streaming the report as SummaryChunkMsg
+// on events when the provider supports it, within timeout overall. Several
+// repos produce a digest. events is closed when done.
+func summarizeCmd(ctx context.Context, run int, p llm.Pipeline, repos []llm.RepoData, dateRange string, timeout time.Duration, events chan<- tea.Msg) tea.Cmd {
 	return func() tea.Msg {
-		summary, err := llm.Summarize(prData, repo, count, dateRange)
-		return SummaryDoneMsg{Summary: summary, Err: err}
+		defer close(events)
+		send := sender(ctx, events)
+		stage, cancel := retry.WithTimeout(ctx, timeout)
+		defer cancel()
+		p.OnChunk = func(text string) {
+			send(SummaryChunkMsg{Run: run, Text: text})
+		}
+		var (
+			res llm.Result
+			err error
+		)
+		if len(repos) == 1 {
+			res, err = p.Run(stage, repos[0])
+		} else {
+			res, err = p.RunDigest(stage, repos, dateRange)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		return SummaryDoneMsg{Run: run, Summary: res.Summary, Err: retry.StageError(stage, "summarizing", timeout, err)}
+	}
+}
+
+func loadHistoryCmd(store *history.Store) tea.Cmd {
+	return func() tea.Msg {
+		if store == nil {
+			return HistoryLoadedMsg{}
+		}
+		reports, err := store.List()
+		return HistoryLoadedMsg{Reports: reports, Err: err}
+	}
+}
+
+func deleteReportCmd(store *history.Store, id string) tea.Cmd {
+	return func() tea.Msg {
+		if err := store.Delete(id); err != nil {
+			return HistoryLoadedMsg{Err: err}
+		}
+		return loadHistoryCmd(store)()
+	}
+}
+
+func saveReportCmd(store *history.Store, r history.Report) tea.Cmd {
+	return func() tea.Msg {
+		if store == nil {
+			return nil
+		}
+		return ReportSavedMsg{Err: store.Save(&r)}
 	}
 }
 
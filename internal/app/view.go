@@ -14,10 +14,15 @@ func (m Model) View() string {
 	outputW := m.width*6/10 - 4
 	panelH := m.height - 2
 
+	leftView := m.Input.View()
+	if m.State == StateHistory {
+		leftView = m.History.View()
+	}
+
 	left := style.InputPanel.
 		Width(inputW).
 		Height(panelH).
-		Render(m.Input.View())
+		Render(leftView)
 
 	right := style.OutputPanel.
 		Width(outputW).
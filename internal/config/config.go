@@ -0,0 +1,555 @@
+// Package config loads pr-news settings. Sources are applied in order, each
+// overriding the previous one: built-in defaults, ~/.pr-news.conf (the shell
+// format shared with the bash version), $XDG_CONFIG_HOME/pr-news/config.toml,
+// the selected profile, PR_NEWS_* environment variables, and finally
+// command-line flags (applied by the caller with Set).
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/llm"
+)
+
+// Config holds every user-tunable setting.
+type Config struct {
+	Days        int
+	Branch      string
+	Workers     int
+	MaxPRs      int // per repo
+	MaxRepos    int // per owner, in the TUI's repo picker
+	TokenBudget int
+	Prompt      string // custom prompt template; "" uses the built-in one
+
+	Limits   github.Limits
+	LLM      llm.Config
+	GitHub   github.Config
+	Timeouts Timeouts
+
+	Profile  string    // applied profile, if any
+	Profiles []Profile // [profile.NAME] tables, in file order
+
+	base      *Config // defaults + files, before any profile or override
+	overrides []entry // env and flag settings, re-applied over a profile
+}
+
+// Timeouts bounds each stage of a run as a whole; zero means no limit.
+// Single GitHub and LLM calls are bounded by GitHub.CallTimeout and
+// LLM.CallTimeout.
+type Timeouts struct {
+	Fetch     time.Duration // listing merged PRs
+	Collect   time.Duration // diffs, comments and reviews
+	Summarize time.Duration // the LLM pipeline
+}
+
+// Default timeouts and retries.
+const (
+	DefaultGitHubCallTimeout = 2 * time.Minute
+	DefaultLLMCallTimeout    = 10 * time.Minute
+	DefaultRetries           = 2
+)
+
+// Profile is a named set of settings layered over the config files.
+type Profile struct {
+	Name  string
+	Repos []string // owner/name patterns (path.Match syntax)
+
+	settings []entry
+}
+
+// Matches reports whether repo matches one of the profile's patterns.
+func (p Profile) Matches(repo string) bool {
+	for _, pat := range p.Repos {
+		if ok, _ := path.Match(pat, repo); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Concrete reports whether every repo entry is a plain owner/name.
+func (p Profile) Concrete() bool {
+	for _, r := range p.Repos {
+		if strings.ContainsAny(r, "*?[") {
+			return false
+		}
+	}
+	return len(p.Repos) > 0
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{
+		Days:        7,
+		Workers:     github.DefaultWorkers,
+		MaxPRs:      github.DefaultMaxPRs,
+		MaxRepos:    github.DefaultMaxRepos,
+		TokenBudget: llm.DefaultTokenBudget,
+		Limits: github.Limits{
+			ThresholdFiles:   github.DefaultThresholdFiles,
+			ThresholdChanges: github.DefaultThresholdChanges,
+			DiffLines:        github.DefaultDiffLines,
+			FileDiffLines:    github.DefaultFileDiffLines,
+			Bots: github.BotFilter{
+				Logins: append([]string(nil), github.DefaultBots...),
+				Suffix: true,
+			},
+			BotPRs:    github.BotPRsKeep,
+			OwnerMode: github.OwnerModeFilter,
+		},
+		LLM: llm.Config{
+			Provider:    llm.ProviderClaudeCLI,
+			CallTimeout: DefaultLLMCallTimeout,
+			Retries:     DefaultRetries,
+		},
+		GitHub: github.Config{
+			Backend:     github.BackendAuto,
+			CallTimeout: DefaultGitHubCallTimeout,
+			Retries:     DefaultRetries,
+		},
+		Timeouts: Timeouts{
+			Fetch:     10 * time.Minute,
+			Collect:   30 * time.Minute,
+			Summarize: 30 * time.Minute,
+		},
+	}
+}
+
+// option describes one setting under its names in each source.
+type option struct {
+	key   string // TOML key; also the canonical name used by Set
+	shell string // ~/.pr-news.conf variable
+	env   string // environment variable
+	set   func(c *Config, v string) error
+}
+
+var options = []option{
+	{"days", "DAYS", "PR_NEWS_DAYS", func(c *Config, v string) error {
+		return parseInt(v, 1, 3650, &c.Days)
+	}},
+	{"branch", "BRANCH", "PR_NEWS_BRANCH", func(c *Config, v string) error {
+		c.Branch = v
+		return nil
+	}},
+	{"prompt", "", "", func(c *Config, v string) error {
+		if _, err := llm.ParsePrompt(v); err != nil {
+			return err
+		}
+		c.Prompt = v
+		return nil
+	}},
+	{"workers", "WORKERS", "PR_NEWS_WORKERS", func(c *Config, v string) error {
+		return parseInt(v, 1, 64, &c.Workers)
+	}},
+	{"max_prs", "MAX_PRS", "PR_NEWS_MAX_PRS", func(c *Config, v string) error {
+		return parseInt(v, 1, github.MaxSearchResults, &c.MaxPRs)
+	}},
+	{"max_repos", "MAX_REPOS", "PR_NEWS_MAX_REPOS", func(c *Config, v string) error {
+		return parseInt(v, 1, 10_000, &c.MaxRepos)
+	}},
+	{"token_budget", "TOKEN_BUDGET", "PR_NEWS_TOKEN_BUDGET", func(c *Config, v string) error {
+		return parseInt(v, 2000, 10_000_000, &c.TokenBudget)
+	}},
+	{"thresholds.files", "THRESHOLD_FILES", "PR_NEWS_THRESHOLD_FILES", func(c *Config, v string) error {
+		return parseInt(v, 1, 1_000_000, &c.Limits.ThresholdFiles)
+	}},
+	{"thresholds.changes", "THRESHOLD_CHANGES", "PR_NEWS_THRESHOLD_CHANGES", func(c *Config, v string) error {
+		return parseInt(v, 1, 100_000_000, &c.Limits.ThresholdChanges)
+	}},
+	{"thresholds.diff_lines", "DIFF_LINES", "PR_NEWS_DIFF_LINES", func(c *Config, v string) error {
+		return parseInt(v, 1, 1_000_000, &c.Limits.DiffLines)
+	}},
+	{"thresholds.file_diff_lines", "FILE_DIFF_LINES", "PR_NEWS_FILE_DIFF_LINES", func(c *Config, v string) error {
+		return parseInt(v, 1, 1_000_000, &c.Limits.FileDiffLines)
+	}},
+	{"include_review_comments", "INCLUDE_REVIEW_COMMENTS", "PR_NEWS_INCLUDE_REVIEW_COMMENTS", func(c *Config, v string) error {
+		var include bool
+		if err := parseBool(v, &include); err != nil {
+			return err
+		}
+		c.Limits.SkipComments = !include
+		return nil
+	}},
+	{"bot_filter", "BOT_FILTER", "PR_NEWS_BOT_FILTER", func(c *Config, v string) error {
+		c.Limits.Bots.Logins = splitList(v)
+		return nil
+	}},
+	{"bot_suffix", "BOT_SUFFIX", "PR_NEWS_BOT_SUFFIX", func(c *Config, v string) error {
+		return parseBool(v, &c.Limits.Bots.Suffix)
+	}},
+	{"bot_patterns", "BOT_PATTERNS", "PR_NEWS_BOT_PATTERNS", func(c *Config, v string) error {
+		var res []*regexp.Regexp
+		for _, p := range splitList(v) {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+			res = append(res, re)
+		}
+		c.Limits.Bots.Patterns = res
+		return nil
+	}},
+	{"bot_prs", "BOT_PRS", "PR_NEWS_BOT_PRS", func(c *Config, v string) error {
+		return oneOf(v, &c.Limits.BotPRs, github.BotPRsKeep, github.BotPRsSkip, github.BotPRsGroup)
+	}},
+	{"generated_files", "GENERATED_FILES", "PR_NEWS_GENERATED_FILES", func(c *Config, v string) error {
+		return parseGlobs(v, &c.Limits.Generated)
+	}},
+	{"paths.include", "INCLUDE_PATHS", "PR_NEWS_INCLUDE_PATHS", func(c *Config, v string) error {
+		return parseGlobs(v, &c.Limits.Scope.Include)
+	}},
+	{"paths.exclude", "EXCLUDE_PATHS", "PR_NEWS_EXCLUDE_PATHS", func(c *Config, v string) error {
+		return parseGlobs(v, &c.Limits.Scope.Exclude)
+	}},
+	{"owner", "OWNER", "PR_NEWS_OWNER", func(c *Config, v string) error {
+		if strings.ContainsAny(v, " \t,") {
+			return fmt.Errorf("expected a single @user or @org/team")
+		}
+		c.Limits.Owner = v
+		return nil
+	}},
+	{"owner_mode", "OWNER_MODE", "PR_NEWS_OWNER_MODE", func(c *Config, v string) error {
+		return oneOf(v, &c.Limits.OwnerMode, github.OwnerModeFilter, github.OwnerModeGroup)
+	}},
+	{"llm.provider", "LLM_PROVIDER", "PR_NEWS_LLM_PROVIDER", func(c *Config, v string) error {
+		return oneOf(v, &c.LLM.Provider, llm.ProviderClaudeCLI, llm.ProviderAnthropic, llm.ProviderOpenAI, llm.ProviderOllama)
+	}},
+	{"llm.model", "LLM_MODEL", "PR_NEWS_LLM_MODEL", func(c *Config, v string) error {
+		c.LLM.Model = v
+		return nil
+	}},
+	{"llm.base_url", "LLM_BASE_URL", "PR_NEWS_LLM_BASE_URL", func(c *Config, v string) error {
+		c.LLM.BaseURL = v
+		return nil
+	}},
+	{"llm.call_timeout", "LLM_CALL_TIMEOUT", "PR_NEWS_LLM_CALL_TIMEOUT", func(c *Config, v string) error {
+		return parseDuration(v, &c.LLM.CallTimeout)
+	}},
+	{"llm.retries", "LLM_RETRIES", "PR_NEWS_LLM_RETRIES", func(c *Config, v string) error {
+		return parseInt(v, 0, 10, &c.LLM.Retries)
+	}},
+	{"github.backend", "GITHUB_BACKEND", "PR_NEWS_GITHUB_BACKEND", func(c *Config, v string) error {
+		return oneOf(v, &c.GitHub.Backend, github.BackendAuto, github.BackendAPI, github.BackendGh)
+	}},
+	{"github.api_url", "GITHUB_API_URL", "GITHUB_API_URL", func(c *Config, v string) error {
+		c.GitHub.BaseURL = v
+		return nil
+	}},
+	{"github.call_timeout", "GITHUB_CALL_TIMEOUT", "PR_NEWS_GITHUB_CALL_TIMEOUT", func(c *Config, v string) error {
+		return parseDuration(v, &c.GitHub.CallTimeout)
+	}},
+	{"github.retries", "GITHUB_RETRIES", "PR_NEWS_GITHUB_RETRIES", func(c *Config, v string) error {
+		return parseInt(v, 0, 10, &c.GitHub.Retries)
+	}},
+	{"timeouts.fetch", "FETCH_TIMEOUT", "PR_NEWS_FETCH_TIMEOUT", func(c *Config, v string) error {
+		return parseDuration(v, &c.Timeouts.Fetch)
+	}},
+	{"timeouts.collect", "COLLECT_TIMEOUT", "PR_NEWS_COLLECT_TIMEOUT", func(c *Config, v string) error {
+		return parseDuration(v, &c.Timeouts.Collect)
+	}},
+	{"timeouts.summarize", "SUMMARIZE_TIMEOUT", "PR_NEWS_SUMMARIZE_TIMEOUT", func(c *Config, v string) error {
+		return parseDuration(v, &c.Timeouts.Summarize)
+	}},
+}
+
+func lookup(match func(option) bool) (option, bool) {
+	for _, o := range options {
+		if match(o) {
+			return o, true
+		}
+	}
+	return option{}, false
+}
+
+// Set overrides a single setting by its TOML key, e.g. "thresholds.files".
+// Overrides take precedence over any profile applied later.
+func (c *Config) Set(key, value string) error {
+	if err := c.set(key, value); err != nil {
+		return err
+	}
+	c.overrides = append(c.overrides, entry{key: key, value: value})
+	return nil
+}
+
+func (c *Config) set(key, value string) error {
+	o, ok := lookup(func(o option) bool { return o.key == key })
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err := o.set(c, strings.Trim(value, " \t\r")); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// LookupProfile returns the profile called name.
+func (c Config) LookupProfile(name string) (Profile, bool) {
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// ProfileFor returns the first profile matching every repo, or "".
+func (c Config) ProfileFor(repos ...string) string {
+	if len(repos) == 0 {
+		return ""
+	}
+	for _, p := range c.Profiles {
+		all := true
+		for _, r := range repos {
+			all = all && p.Matches(r)
+		}
+		if all {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// WithProfile returns the config with the named profile applied over the
+// config files and under the env and flag overrides. "" applies none.
+func (c Config) WithProfile(name string) (Config, error) {
+	out := c
+	if c.base != nil {
+		out = *c.base
+		out.base, out.overrides = c.base, c.overrides
+	}
+	out.Profile = ""
+	if name != "" {
+		p, ok := c.LookupProfile(name)
+		if !ok {
+			return c, fmt.Errorf("unknown profile %q", name)
+		}
+		for _, e := range p.settings {
+			if err := out.set(e.key, e.value); err != nil {
+				return c, fmt.Errorf("profile %s: %w", name, err)
+			}
+		}
+		out.Profile = name
+	}
+	for _, e := range c.overrides {
+		if err := out.set(e.key, e.value); err != nil {
+			return c, err
+		}
+	}
+	return out, nil
+}
+
+// ShellPath returns ~/.pr-news.conf.
+func ShellPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".pr-news.conf")
+}
+
+// TOMLPath returns $XDG_CONFIG_HOME/pr-news/config.toml.
+func TOMLPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "pr-news", "config.toml")
+}
+
+// Load returns the defaults overlaid with the config files and environment.
+// path, if set, replaces the default files; a .toml extension selects the
+// TOML format, anything else the shell format.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	files := []string{ShellPath(), TOMLPath()}
+	if path != "" {
+		files = []string{path}
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			if os.IsNotExist(err) && path == "" {
+				continue // 기본 경로의 파일은 없어도 된다
+			}
+			return cfg, err
+		}
+		if err := cfg.loadFile(f, data); err != nil {
+			return cfg, err
+		}
+	}
+	base := cfg
+	cfg.base = &base
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string, data []byte) error {
+	if strings.HasSuffix(path, ".toml") {
+		settings, profiles, err := parseTOML(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, e := range settings {
+			if err := c.set(e.key, e.value); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		for _, p := range profiles {
+			for _, e := range p.settings {
+				if err := c.addProfileSetting(p.name, e.key, e); err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+			}
+		}
+		return nil
+	}
+
+	entries, err := parseShell(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, e := range entries {
+		// 셸 파일에는 다른 변수가 있을 수 있으므로 모르는 키는 무시
+		o, ok := lookup(func(o option) bool { return o.shell != "" && o.shell == e.key })
+		if !ok {
+			continue
+		}
+		if err := o.set(c, e.value); err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", path, e.line, e.key, err)
+		}
+	}
+	return nil
+}
+
+// addProfileSetting records key for profile name, validating it against a
+// scratch config so errors are reported while loading the file.
+func (c *Config) addProfileSetting(name, key string, e entry) error {
+	i := slices.IndexFunc(c.Profiles, func(p Profile) bool { return p.Name == name })
+	if i < 0 {
+		c.Profiles = append(c.Profiles, Profile{Name: name})
+		i = len(c.Profiles) - 1
+	}
+	p := &c.Profiles[i]
+
+	if key == "repos" {
+		p.Repos = splitList(e.value)
+		for _, r := range p.Repos {
+			if _, err := path.Match(r, ""); err != nil || !strings.Contains(r, "/") {
+				return fmt.Errorf("profile %s: invalid repo pattern %q", name, r)
+			}
+		}
+		return nil
+	}
+	if strings.HasPrefix(key, "github.") {
+		return fmt.Errorf("profile %s: %s cannot be set per profile", name, key)
+	}
+	scratch := Default()
+	if err := scratch.set(key, e.value); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	p.settings = append(p.settings, entry{key: key, value: e.value, line: e.line})
+	return nil
+}
+
+func (c *Config) applyEnv() error {
+	for _, o := range options {
+		if o.env == "" {
+			continue
+		}
+		v, ok := os.LookupEnv(o.env)
+		if !ok || v == "" {
+			continue
+		}
+		if err := c.Set(o.key, v); err != nil {
+			return fmt.Errorf("%s: %w", o.env, err)
+		}
+	}
+	return nil
+}
+
+func parseInt(v string, lo, hi int, dst *int) error {
+	n, err := strconv.Atoi(strings.ReplaceAll(v, "_", ""))
+	if err != nil {
+		return fmt.Errorf("invalid number %q", v)
+	}
+	if n < lo || n > hi {
+		return fmt.Errorf("%d out of range [%d, %d]", n, lo, hi)
+	}
+	*dst = n
+	return nil
+}
+
+// parseDuration reads a Go duration ("90s", "2m30s") or a number of
+// seconds; 0 disables the limit.
+func parseDuration(v string, dst *time.Duration) error {
+	if n, err := strconv.Atoi(v); err == nil {
+		v = strconv.Itoa(n) + "s"
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q", v)
+	}
+	*dst = d
+	return nil
+}
+
+func parseBool(v string, dst *bool) error {
+	switch strings.ToLower(v) {
+	case "true", "yes", "on", "1":
+		*dst = true
+	case "false", "no", "off", "0":
+		*dst = false
+	default:
+		return fmt.Errorf("invalid boolean %q", v)
+	}
+	return nil
+}
+
+func oneOf(v string, dst *string, allowed ...string) error {
+	for _, a := range allowed {
+		if v == a {
+			*dst = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of %s", v, strings.Join(allowed, ", "))
+}
+
+// splitList splits a list, dropping blanks. TOML arrays arrive one item
+// per line; shell and environment values are comma-separated.
+func splitList(v string) []string {
+	sep := ","
+	if strings.Contains(v, "\n") {
+		sep = "\n"
+	}
+	var out []string
+	for _, s := range strings.Split(v, sep) {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// parseGlobs reads a glob list and rejects malformed patterns.
+func parseGlobs(v string, dst *[]string) error {
+	globs := splitList(v)
+	for _, g := range globs {
+		if _, err := path.Match(strings.ReplaceAll(g, "**", "*"), ""); err != nil {
+			return fmt.Errorf("invalid glob %q", g)
+		}
+	}
+	*dst = globs
+	return nil
+}
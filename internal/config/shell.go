@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// entry is one key/value pair read from a config file.
+type entry struct {
+	key   string
+	value string
+	line  int
+}
+
+// parseShell reads the KEY=VALUE subset of shell syntax that
+// ~/.pr-news.conf uses: optional "export", single or double quotes, and
+// # comments. Anything the bash version would execute (commands, $(...),
+// variable expansion) is rejected rather than evaluated.
+func parseShell(src string) ([]entry, error) {
+	var entries []entry
+	for i, line := range strings.Split(src, "\n") {
+		n := i + 1
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok || !isShellName(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		value, err := shellValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", n, key, err)
+		}
+		entries = append(entries, entry{key: key, value: value, line: n})
+	}
+	return entries, nil
+}
+
+func isShellName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// shellValue unquotes a value and strips a trailing comment.
+func shellValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := strings.IndexByte(raw[1:], '"')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		v := raw[1 : end+1]
+		if strings.Contains(v, "$") || strings.Contains(v, "`") {
+			return "", fmt.Errorf("expansion is not supported")
+		}
+		return v, trailing(raw[end+2:])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return raw[1 : end+1], trailing(raw[end+2:])
+	}
+
+	v, _, _ := strings.Cut(raw, " #")
+	v = strings.TrimSpace(v)
+	if strings.ContainsAny(v, "$`;|&<> ") {
+		return "", fmt.Errorf("unquoted value %q must be a single word", v)
+	}
+	return v, nil
+}
+
+// trailing accepts only whitespace or a comment after a quoted value.
+func trailing(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest == "" || strings.HasPrefix(rest, "#") {
+		return nil
+	}
+	return fmt.Errorf("unexpected %q after value", rest)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseShell(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"plain", "DAYS=7\nTHRESHOLD_FILES=10", []string{"DAYS=7", "THRESHOLD_FILES=10"}},
+		{"export", "export LLM_PROVIDER=ollama", []string{"LLM_PROVIDER=ollama"}},
+		{"comments and blanks", "# header\n\n  DAYS=3 # trailing\n", []string{"DAYS=3"}},
+		{"double quotes", `BOT_FILTER="a,b, c" # bots`, []string{"BOT_FILTER=a,b, c"}},
+		{"single quotes keep $", `BOT_PATTERNS='^ci-.*$'`, []string{"BOT_PATTERNS=^ci-.*$"}},
+		{"empty", "OWNER=\nMODEL=\"\"", []string{"OWNER=", "MODEL="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseShell(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pairs(entries); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseShellErrors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"no =", "DAYS 7", "line 1: expected KEY=VALUE"},
+		{"bad name", "1DAYS=7", "line 1: expected KEY=VALUE"},
+		{"unterminated double quote", "A=1\nB=\"abc", "line 2: B: unterminated quote"},
+		{"unterminated single quote", "B='abc", "line 1: B: unterminated quote"},
+		{"expansion", `B="$HOME/x"`, "line 1: B: expansion is not supported"},
+		{"command substitution", "B=$(whoami)", "must be a single word"},
+		{"unquoted spaces", "B=a b", "must be a single word"},
+		{"junk after quote", `B="a" c`, `line 1: B: unexpected "c" after value`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseShell(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlFile is the layout of config.toml. Field tags are the option keys;
+// values are checked by the options table, as for the other sources.
+type tomlFile struct {
+	tomlSettings
+	Profile map[string]tomlProfile `toml:"profile"`
+}
+
+type tomlProfile struct {
+	tomlSettings
+	Repos *tomlValue `toml:"repos"`
+}
+
+type tomlSettings struct {
+	Days        *tomlValue `toml:"days"`
+	Branch      *tomlValue `toml:"branch"`
+	Prompt      *tomlValue `toml:"prompt"`
+	Workers     *tomlValue `toml:"workers"`
+	MaxPRs      *tomlValue `toml:"max_prs"`
+	MaxRepos    *tomlValue `toml:"max_repos"`
+	TokenBudget *tomlValue `toml:"token_budget"`
+	Thresholds  struct {
+		Files         *tomlValue `toml:"files"`
+		Changes       *tomlValue `toml:"changes"`
+		DiffLines     *tomlValue `toml:"diff_lines"`
+		FileDiffLines *tomlValue `toml:"file_diff_lines"`
+	} `toml:"thresholds"`
+	IncludeReviewComments *tomlValue `toml:"include_review_comments"`
+	BotFilter             *tomlValue `toml:"bot_filter"`
+	BotSuffix             *tomlValue `toml:"bot_suffix"`
+	BotPatterns           *tomlValue `toml:"bot_patterns"`
+	BotPRs                *tomlValue `toml:"bot_prs"`
+	GeneratedFiles        *tomlValue `toml:"generated_files"`
+	Paths                 struct {
+		Include *tomlValue `toml:"include"`
+		Exclude *tomlValue `toml:"exclude"`
+	} `toml:"paths"`
+	Owner     *tomlValue `toml:"owner"`
+	OwnerMode *tomlValue `toml:"owner_mode"`
+	LLM       struct {
+		Provider    *tomlValue `toml:"provider"`
+		Model       *tomlValue `toml:"model"`
+		BaseURL     *tomlValue `toml:"base_url"`
+		CallTimeout *tomlValue `toml:"call_timeout"`
+		Retries     *tomlValue `toml:"retries"`
+	} `toml:"llm"`
+	GitHub struct {
+		Backend     *tomlValue `toml:"backend"`
+		APIURL      *tomlValue `toml:"api_url"`
+		CallTimeout *tomlValue `toml:"call_timeout"`
+		Retries     *tomlValue `toml:"retries"`
+	} `toml:"github"`
+	Timeouts struct {
+		Fetch     *tomlValue `toml:"fetch"`
+		Collect   *tomlValue `toml:"collect"`
+		Summarize *tomlValue `toml:"summarize"`
+	} `toml:"timeouts"`
+}
+
+// tomlValue is a setting in the string form the options table reads.
+// Arrays become one item per line, newline-terminated, so that splitList
+// does not split an item on commas.
+type tomlValue string
+
+func (v *tomlValue) UnmarshalTOML(data any) error {
+	switch d := data.(type) {
+	case []any:
+		var b strings.Builder
+		for _, item := range d {
+			s, err := scalar(item)
+			if err != nil {
+				return err
+			}
+			b.WriteString(s + "\n")
+		}
+		*v = tomlValue(b.String())
+		return nil
+	default:
+		s, err := scalar(d)
+		*v = tomlValue(s)
+		return err
+	}
+}
+
+func scalar(data any) (string, error) {
+	switch d := data.(type) {
+	case string:
+		return d, nil
+	case int64:
+		return strconv.FormatInt(d, 10), nil
+	case bool:
+		return strconv.FormatBool(d), nil
+	}
+	return "", fmt.Errorf("unsupported value %v (%T)", data, data)
+}
+
+// tomlProfileSettings are the settings of one [profile.NAME] table.
+type tomlProfileSettings struct {
+	name     string
+	settings []entry // repos first, then in option order
+}
+
+// parseTOML decodes config.toml into its settings, keyed by option key
+// ("thresholds.files"), and its profiles in file order. Keys the layout
+// does not know are errors.
+func parseTOML(src string) ([]entry, []tomlProfileSettings, error) {
+	var f tomlFile
+	md, err := toml.Decode(src, &f)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unknown := md.Undecoded(); len(unknown) > 0 {
+		return nil, nil, fmt.Errorf("unknown setting %q", unknown[0].String())
+	}
+
+	var profiles []tomlProfileSettings
+	seen := map[string]bool{}
+	for _, k := range md.Keys() {
+		if len(k) < 2 || k[0] != "profile" || seen[k[1]] {
+			continue
+		}
+		seen[k[1]] = true
+		p := f.Profile[k[1]]
+		ps := tomlProfileSettings{name: k[1]}
+		if p.Repos != nil {
+			ps.settings = append(ps.settings, entry{key: "repos", value: string(*p.Repos)})
+		}
+		ps.settings = append(ps.settings, settingEntries("", reflect.ValueOf(p.tomlSettings))...)
+		profiles = append(profiles, ps)
+	}
+	return settingEntries("", reflect.ValueOf(f.tomlSettings)), profiles, nil
+}
+
+// settingEntries lists the settings set in s, a tomlSettings or one of its
+// tables, in field order.
+func settingEntries(prefix string, s reflect.Value) []entry {
+	var entries []entry
+	for i := range s.NumField() {
+		key := prefix + s.Type().Field(i).Tag.Get("toml")
+		switch v := s.Field(i).Interface().(type) {
+		case *tomlValue:
+			if v != nil {
+				entries = append(entries, entry{key: key, value: string(*v)})
+			}
+		default:
+			entries = append(entries, settingEntries(key+".", s.Field(i))...)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// pairs renders entries as key=value for comparison.
+func pairs(entries []entry) []string {
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.key+"="+e.value)
+	}
+	return out
+}
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"integer and boolean", "days = 14\nbot_suffix = false\n", []string{"days=14", "bot_suffix=false"}},
+		{"integer with underscores", "token_budget = 100_000", []string{"token_budget=100000"}},
+		{"comments", "# top\ndays = 3 # trailing\n\n", []string{"days=3"}},
+		{"table", "[thresholds]\nfiles = 20\nchanges = 1000", []string{"thresholds.files=20", "thresholds.changes=1000"}},
+		{"dotted keys", "llm.provider = \"ollama\"\nthresholds . diff_lines = 50", []string{"thresholds.diff_lines=50", "llm.provider=ollama"}},
+		{"inline table", `timeouts = { fetch = "5m", collect = 600 }`, []string{"timeouts.fetch=5m", "timeouts.collect=600"}},
+		{"array", `bot_filter = ["a", 'b', "c"]`, []string{"bot_filter=a\nb\nc\n"}},
+		{"array item with comma", `generated_files = ["*.{pb,gen}.go"]`, []string{"generated_files=*.{pb,gen}.go\n"}},
+		{"empty array", "bot_filter = []", []string{"bot_filter="}},
+		{"literal string", `prompt = 'C:\path "x"'`, []string{`prompt=C:\path "x"`}},
+		{"escapes", `branch = "a\tb \u00e9"`, []string{"branch=a\tb é"}},
+		{"multi-line basic", "prompt = \"\"\"\nline 1\nline \\\"2\\\"\n\"\"\"", []string{"prompt=line 1\nline \"2\"\n"}},
+		{"multi-line literal", "prompt = '''\n{{.Repo}} \\n\n'''", []string{"prompt={{.Repo}} \\n\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, profiles, err := parseTOML(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(profiles) != 0 {
+				t.Errorf("profiles = %v, want none", profiles)
+			}
+			if got := pairs(settings); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLProfiles(t *testing.T) {
+	src := `days = 7
+
+[profile.mono]
+thresholds.diff_lines = 1500
+repos = ["org/mono"]
+
+[profile."my.docs"]
+llm = { provider = "ollama" }
+days = 1
+`
+	settings, profiles, err := parseTOML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pairs(settings); !slices.Equal(got, []string{"days=7"}) {
+		t.Errorf("settings = %q, want days=7", got)
+	}
+	want := []struct {
+		name     string
+		settings []string
+	}{
+		{"mono", []string{"repos=org/mono\n", "thresholds.diff_lines=1500"}},
+		{"my.docs", []string{"days=1", "llm.provider=ollama"}},
+	}
+	if len(profiles) != len(want) {
+		t.Fatalf("got %d profiles, want %d", len(profiles), len(want))
+	}
+	for i, w := range want {
+		if p := profiles[i]; p.name != w.name || !slices.Equal(pairs(p.settings), w.settings) {
+			t.Errorf("profile %d = %s %q, want %s %q", i, p.name, pairs(p.settings), w.name, w.settings)
+		}
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"syntax error", `days = "abc`, "line 1"},
+		{"duplicate key", "days = 1\ndays = 2", "line 2"},
+		{"unknown key", "dayz = 1", `unknown setting "dayz"`},
+		{"unknown key in table", "[llm]\nprovder = \"x\"", `unknown setting "llm.provder"`},
+		{"unknown profile key", "[profile.x]\nfoo = 1", `unknown setting "profile.x.foo"`},
+		{"float", "days = 1.5", "unsupported value 1.5"},
+		{"table for a value", "[days]\nx = 1", "unsupported value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseTOML(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestTOMLLayoutCoversOptions keeps tomlSettings in step with the options
+// table: every option is a TOML key and every TOML key an option.
+func TestTOMLLayoutCoversOptions(t *testing.T) {
+	var keys func(prefix string, typ reflect.Type) []string
+	keys = func(prefix string, typ reflect.Type) []string {
+		var out []string
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			key := prefix + f.Tag.Get("toml")
+			if f.Type.Kind() == reflect.Struct {
+				out = append(out, keys(key+".", f.Type)...)
+			} else {
+				out = append(out, key)
+			}
+		}
+		return out
+	}
+	var want []string
+	for _, o := range options {
+		want = append(want, o.key)
+	}
+	if got := keys("", reflect.TypeFor[tomlSettings]()); !slices.Equal(got, want) {
+		t.Errorf("TOML keys = %q, want the option keys %q", got, want)
+	}
+}
@@ -0,0 +1,244 @@
+// Package doctor checks that the tools and credentials pr-news depends on
+// are in place before a run, and suggests a fix for each one that is not.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/history"
+	"github.com/eddy/pr-news/internal/llm"
+	"github.com/eddy/pr-news/internal/proc"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	OK      Status = iota
+	Skipped        // not needed with the current settings
+	Warning        // runs work, with something missing
+	Failed         // runs will fail
+)
+
+// Mark is the checklist symbol for s.
+func (s Status) Mark() string {
+	switch s {
+	case Skipped:
+		return "-"
+	case Warning:
+		return "!"
+	case Failed:
+		return "✗"
+	}
+	return "✓"
+}
+
+// Check is the result of one check.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string
+	Hint   string // how to fix a warning or failure
+}
+
+// Report is the checks in a fixed order.
+type Report []Check
+
+// Failed reports whether any check failed.
+func (r Report) Failed() bool {
+	return slices.ContainsFunc(r, func(c Check) bool { return c.Status == Failed })
+}
+
+// Warnings describes the checks that passed with a warning.
+func (r Report) Warnings() []string {
+	var out []string
+	for _, c := range r {
+		if c.Status == Warning {
+			out = append(out, c.Name+": "+c.Detail)
+		}
+	}
+	return out
+}
+
+// String renders r as a plain checklist with hints under the checks that
+// need them.
+func (r Report) String() string {
+	width := 0
+	for _, c := range r {
+		width = max(width, len(c.Name))
+	}
+	var b strings.Builder
+	for _, c := range r {
+		fmt.Fprintf(&b, "%s %-*s  %s\n", c.Status.Mark(), width, c.Name, c.Detail)
+		if c.Hint != "" && (c.Status == Warning || c.Status == Failed) {
+			fmt.Fprintf(&b, "  %*s  → %s\n", width, "", c.Hint)
+		}
+	}
+	return b.String()
+}
+
+// Options are the clients to check, as built from the settings. A client
+// that could not be built is nil, with the reason in its Err field.
+type Options struct {
+	GitHub     github.Client
+	GitHubErr  error
+	Summarizer llm.Summarizer
+	LLMErr     error
+}
+
+// Timeout bounds each check.
+const Timeout = 15 * time.Second
+
+// ClipboardCommand is the command the TUI copies summaries with.
+const ClipboardCommand = "pbcopy"
+
+// Run runs every check concurrently and returns the report.
+func Run(ctx context.Context, opts Options) Report {
+	checks := []func(context.Context, Options) []Check{checkGh, checkGitHub, checkLLM, checkClipboard}
+	results := make([][]Check, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, Timeout)
+			defer cancel()
+			results[i] = check(cctx, opts)
+		}()
+	}
+	wg.Wait()
+	return slices.Concat(results...)
+}
+
+func usesGh(opts Options) bool {
+	_, ok := opts.GitHub.(*github.GhCLI)
+	return ok
+}
+
+// checkGh looks for the gh binary, which the API backend does not need.
+func checkGh(ctx context.Context, opts Options) []Check {
+	c := Check{Name: "gh"}
+	version, err := github.GhVersion(ctx)
+	switch {
+	case err == nil:
+		c.Detail = version
+	case errors.Is(err, proc.ErrNotInstalled) && !usesGh(opts) && opts.GitHubErr == nil:
+		c.Status, c.Detail = Skipped, "not installed; not needed with the API backend"
+	default:
+		c.Status, c.Detail, c.Hint = Failed, describe(err), proc.Hint(err)
+	}
+	return []Check{c}
+}
+
+// checkGitHub signs in as the configured account and compares the
+// token's scopes with github.RequiredScopes.
+func checkGitHub(ctx context.Context, opts Options) []Check {
+	auth := Check{Name: "GitHub auth"}
+	scopes := Check{Name: "GitHub scopes", Status: Skipped, Detail: "needs GitHub auth"}
+	if opts.GitHubErr != nil {
+		auth.Status, auth.Detail = Failed, describe(opts.GitHubErr)
+		auth.Hint = "Set GITHUB_TOKEN, or run `gh auth login`."
+		return []Check{auth, scopes}
+	}
+	v, err := opts.GitHub.Viewer(ctx)
+	if err != nil {
+		auth.Status, auth.Detail, auth.Hint = Failed, describe(err), githubHint(err)
+		return []Check{auth, scopes}
+	}
+	backend := "API"
+	if usesGh(opts) {
+		backend = "gh"
+	}
+	auth.Detail = fmt.Sprintf("signed in as %s (%s backend)", v.Login, backend)
+
+	scopes.Status = OK
+	switch missing := v.MissingScopes(); {
+	case v.Scopes == nil:
+		scopes.Detail = "not listed by this token (fine-grained or app token)"
+	case len(missing) > 0:
+		scopes.Status = Warning
+		scopes.Detail = fmt.Sprintf("missing %s; private or organization repos may not be listed", strings.Join(missing, ", "))
+		scopes.Hint = fmt.Sprintf("Run `gh auth refresh -s %s`, or give GITHUB_TOKEN those scopes.", strings.Join(missing, ","))
+	default:
+		scopes.Detail = strings.Join(v.Scopes, ", ")
+	}
+	return []Check{auth, scopes}
+}
+
+func githubHint(err error) string {
+	if hint := proc.Hint(err); hint != "" {
+		return hint
+	}
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		return "The token was rejected; set a valid GITHUB_TOKEN, or unset it and run `gh auth login`."
+	}
+	return "Check github.api_url and your network connection."
+}
+
+// checkLLM asks the provider to confirm it is reachable, when it can.
+func checkLLM(ctx context.Context, opts Options) []Check {
+	c := Check{Name: "LLM"}
+	if opts.LLMErr != nil {
+		c.Status, c.Detail = Failed, describe(opts.LLMErr)
+		c.Hint = "Set the provider's API key, or choose another provider with --provider."
+		return []Check{c}
+	}
+	checker, ok := opts.Summarizer.(llm.Checker)
+	if !ok {
+		c.Status, c.Detail = Skipped, opts.Summarizer.Name()+": cannot be checked"
+		return []Check{c}
+	}
+	detail, err := checker.Check(ctx)
+	if err != nil {
+		c.Status, c.Detail, c.Hint = Failed, opts.Summarizer.Name()+": "+describe(err), llmHint(err)
+		return []Check{c}
+	}
+	c.Detail = opts.Summarizer.Name() + ": " + detail
+	return []Check{c}
+}
+
+func llmHint(err error) string {
+	if hint := proc.Hint(err); hint != "" {
+		return hint
+	}
+	var se *llm.StatusError
+	switch {
+	case errors.As(err, &se) && (se.StatusCode == http.StatusUnauthorized || se.StatusCode == http.StatusForbidden):
+		return "Check the API key in PR_NEWS_LLM_API_KEY, ANTHROPIC_API_KEY or OPENAI_API_KEY."
+	case errors.Is(err, llm.ErrModelMissing):
+		return "Pull the model, or set llm.model to one the provider has."
+	}
+	return "Check llm.base_url and that the provider is running and reachable."
+}
+
+// checkClipboard looks for the command the TUI's c key copies with.
+func checkClipboard(context.Context, Options) []Check {
+	c := Check{Name: "Clipboard"}
+	if path, err := exec.LookPath(ClipboardCommand); err == nil {
+		c.Detail = path
+	} else {
+		c.Status = Warning
+		c.Detail = ClipboardCommand + " not found; c cannot copy summaries"
+		c.Hint = "Reports are still saved in " + history.DefaultDir() + "."
+	}
+	return []Check{c}
+}
+
+// describe shortens err to one checklist line.
+func describe(err error) string {
+	if errors.Is(err, proc.ErrNotInstalled) {
+		return "not installed"
+	}
+	line, _, _ := strings.Cut(err.Error(), "\n")
+	return line
+}
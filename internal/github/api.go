@@ -0,0 +1,376 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eddy/pr-news/internal/retry"
+)
+
+const defaultAPIURL = "https://api.github.com"
+
+// API talks to the GitHub REST and GraphQL APIs directly.
+type API struct {
+	BaseURL string // REST base, no trailing slash
+	Token   string
+	Retry   retry.Policy // bounds each request and retries transient failures
+
+	http   *http.Client
+	limits *limiter
+}
+
+// NewAPI returns an API client. An empty baseURL means github.com.
+func NewAPI(baseURL, token string) *API {
+	if baseURL == "" {
+		baseURL = defaultAPIURL
+	}
+	return &API{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		http:    &http.Client{}, // 요청별 제한은 Retry.Timeout
+		limits:  newLimiter(),
+	}
+}
+
+// graphqlURL derives the GraphQL endpoint from the REST base:
+// api.github.com → /graphql, GHES HOST/api/v3 → HOST/api/graphql.
+func (a *API) graphqlURL() string {
+	if base, ok := strings.CutSuffix(a.BaseURL, "/api/v3"); ok {
+		return base + "/api/graphql"
+	}
+	return a.BaseURL + "/graphql"
+}
+
+// APIError is a non-2xx response from GitHub.
+type APIError struct {
+	StatusCode int
+	Message    string
+	URL        string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github %s: %d %s", e.URL, e.StatusCode, e.Message)
+}
+
+// do performs a request against url (absolute, or a path relative to BaseURL)
+// and returns the response body. Rate-limited requests are retried after
+// the wait GitHub asks for (see limiter).
+func (a *API) do(ctx context.Context, method, target, accept string, body any) ([]byte, error) {
+	_, data, err := a.request(ctx, method, target, accept, body)
+	return data, err
+}
+
+// request is do, also returning the final response's headers.
+func (a *API) request(ctx context.Context, method, target, accept string, body any) (http.Header, []byte, error) {
+	if !strings.Contains(target, "://") {
+		target = a.BaseURL + "/" + strings.TrimLeft(target, "/")
+	}
+	resource := ResourceCore
+	if target == a.graphqlURL() {
+		resource = ResourceGraphQL
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, nil, err
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		if err := a.limits.ready(ctx, resource); err != nil {
+			return nil, nil, err
+		}
+		var (
+			resp *http.Response
+			data []byte
+		)
+		err := retry.Do(ctx, a.Retry, "GitHub "+method+" "+requestPath(target), apiTransient, func(ctx context.Context) error {
+			var err error
+			resp, data, err = a.send(ctx, method, target, accept, payload)
+			if err == nil && resp.StatusCode/100 == 5 {
+				return apiError(resp, data)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if w, limited := rateLimitWait(resource, resp, data, attempt); limited {
+			if attempt > maxRateLimitRetries {
+				return nil, nil, &RateLimitError{Resource: resource}
+			}
+			if err := a.limits.pause(ctx, w); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if resp.StatusCode/100 != 2 {
+			return nil, nil, apiError(resp, data)
+		}
+		return resp.Header, data, nil
+	}
+}
+
+func apiError(resp *http.Response, data []byte) *APIError {
+	var e struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &e) != nil || e.Message == "" {
+		e.Message = strings.TrimSpace(string(data))
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: e.Message, URL: resp.Request.URL.Path}
+}
+
+// apiTransient reports whether a failed request is worth repeating:
+// network errors, timeouts and 5xx responses.
+func apiTransient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode/100 == 5
+	}
+	return retry.Transient(err)
+}
+
+// requestPath returns target without scheme, host and query, for messages.
+func requestPath(target string) string {
+	if u, err := url.Parse(target); err == nil {
+		return u.Path
+	}
+	return target
+}
+
+// send performs one request and reads the whole response.
+func (a *API) send(ctx context.Context, method, target, accept string, payload []byte) (*http.Response, []byte, error) {
+	var rd io.Reader
+	if payload != nil {
+		rd = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, rd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if accept == "" {
+		accept = "application/vnd.github+json"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	a.limits.observe(resp.Header)
+	return resp, data, nil
+}
+
+func (a *API) getJSON(ctx context.Context, path string, out any) error {
+	data, err := a.do(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// graphql runs query and decodes its "data" object into out.
+func (a *API) graphql(ctx context.Context, query string, vars map[string]any, out any) error {
+	data, err := a.do(ctx, http.MethodPost, a.graphqlURL(), "", map[string]any{
+		"query":     query,
+		"variables": vars,
+	})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+func (a *API) ListRepos(ctx context.Context, limit int) ([]string, bool, error) {
+	if limit <= 0 {
+		limit = DefaultMaxRepos
+	}
+	type repo struct {
+		FullName string `json:"full_name"`
+	}
+
+	own, truncated, err := getPaged[repo](ctx, a, "user/repos?affiliation=owner&sort=pushed", limit)
+	if err != nil {
+		return nil, false, fmt.Errorf("listing repos: %w", err)
+	}
+	repos := make(map[string]bool)
+	for _, r := range own {
+		repos[r.FullName] = true
+	}
+
+	// Org repos
+	orgs, _, _ := getPaged[struct {
+		Login string `json:"login"`
+	}](ctx, a, "user/orgs", 1000)
+	for _, org := range orgs {
+		oRepos, more, _ := getPaged[repo](ctx, a, fmt.Sprintf("orgs/%s/repos?sort=pushed", url.PathEscape(org.Login)), limit)
+		truncated = truncated || more
+		for _, r := range oRepos {
+			repos[r.FullName] = true
+		}
+	}
+
+	result := make([]string, 0, len(repos))
+	for r := range repos {
+		result = append(result, r)
+	}
+	sort.Strings(result)
+	return result, truncated, nil
+}
+
+func (a *API) ListMergedPRs(ctx context.Context, repo string, since time.Time, baseBranch string, limit int) ([]PR, int, error) {
+	return pageMergedPRs(ctx, mergedPRsSearch(repo, since, baseBranch), limit, func(vars map[string]any, out *searchPage) error {
+		return a.graphql(ctx, mergedPRsQuery, vars, out)
+	})
+}
+
+func (a *API) OnRateLimit(f func(RateLimitWait)) { a.limits.setNotify(f) }
+
+func (a *API) RateLimits(ctx context.Context) ([]RateLimit, error) {
+	var resp rateLimitResponse
+	if err := a.getJSON(ctx, "rate_limit", &resp); err != nil {
+		return nil, fmt.Errorf("reading rate limits: %w", err)
+	}
+	return resp.limits(), nil
+}
+
+func (a *API) Viewer(ctx context.Context) (Viewer, error) {
+	header, data, err := a.request(ctx, http.MethodGet, "user", "", nil)
+	if err != nil {
+		return Viewer{}, fmt.Errorf("checking authentication: %w", err)
+	}
+	return parseViewer(header, data)
+}
+
+func (a *API) GetPRDiff(ctx context.Context, repo string, number int) (string, error) {
+	data, err := a.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/pulls/%d", repo, number), "application/vnd.github.diff", nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotAcceptable {
+		return "", fmt.Errorf("%w: %s", ErrDiffTooLarge, apiErr.Message)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// maxFiles caps ListFiles; GitHub itself lists at most 3000 files.
+const maxFiles = 3000
+
+func (a *API) ListFiles(ctx context.Context, repo string, number int) ([]string, error) {
+	resp, _, err := getPaged[struct {
+		Filename         string `json:"filename"`
+		PreviousFilename string `json:"previous_filename"`
+	}](ctx, a, fmt.Sprintf("repos/%s/pulls/%d/files", repo, number), maxFiles)
+	if err != nil {
+		return nil, fmt.Errorf("listing files: %w", err)
+	}
+	var files []string
+	for _, f := range resp {
+		files = append(files, f.Filename)
+		if f.PreviousFilename != "" {
+			files = append(files, f.PreviousFilename)
+		}
+	}
+	return files, nil
+}
+
+func (a *API) GetFile(ctx context.Context, repo, path, ref string) (string, error) {
+	target := fmt.Sprintf("repos/%s/contents/%s", repo, path)
+	if ref != "" {
+		target += "?ref=" + url.QueryEscape(ref)
+	}
+	data, err := a.do(ctx, http.MethodGet, target, "application/vnd.github.raw", nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// maxComments caps ListComments.
+const maxComments = 1000
+
+func (a *API) ListComments(ctx context.Context, repo string, number int) ([]Comment, error) {
+	resp, _, err := getPaged[struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		AuthorAssociation string `json:"author_association"`
+		Body              string `json:"body"`
+	}](ctx, a, fmt.Sprintf("repos/%s/issues/%d/comments", repo, number), maxComments)
+	if err != nil {
+		return nil, fmt.Errorf("listing comments: %w", err)
+	}
+	comments := make([]Comment, 0, len(resp))
+	for _, c := range resp {
+		comments = append(comments, Comment{
+			Author:            c.User.Login,
+			AuthorAssociation: c.AuthorAssociation,
+			Body:              c.Body,
+		})
+	}
+	return comments, nil
+}
+
+func (a *API) ListReviews(ctx context.Context, repo string, number int) (Reviews, error) {
+	vars, err := reviewsVars(repo, number)
+	if err != nil {
+		return Reviews{}, err
+	}
+	var resp reviewsResponse
+	if err := a.graphql(ctx, reviewsQuery, vars, &resp); err != nil {
+		return Reviews{}, fmt.Errorf("listing reviews: %w", err)
+	}
+	return resp.reviews(), nil
+}
+
+// hostOf returns the host of a GitHub API base URL ("" if unset or invalid).
+// GHES hosts are returned as-is so `gh auth token --hostname` finds them.
+func hostOf(baseURL string) string {
+	if baseURL == "" {
+		return ""
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
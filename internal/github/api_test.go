@@ -0,0 +1,40 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestAPIListCommentsPaginates(t *testing.T) {
+	const n = 150
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/o/r/issues/7/comments" {
+			http.NotFound(w, r)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		var out []map[string]any
+		for i := (page - 1) * perPage; i < min(page*perPage, n); i++ {
+			out = append(out, map[string]any{"user": map[string]string{"login": "u"}, "body": fmt.Sprint(i)})
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	comments, err := NewAPI(srv.URL, "").ListComments(context.Background(), "o/r", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != n {
+		t.Fatalf("got %d comments, want %d", len(comments), n)
+	}
+	if last := comments[n-1].Body; last != fmt.Sprint(n-1) {
+		t.Errorf("last comment = %q, want %q", last, fmt.Sprint(n-1))
+	}
+}
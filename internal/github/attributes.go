@@ -0,0 +1,188 @@
+package github
+
+import (
+	"path"
+	"strings"
+)
+
+// Attributes holds the linguist-related rules of a .gitattributes file.
+type Attributes struct {
+	rules []attrRule
+}
+
+// attrRule is one pattern line. A nil field leaves the attribute as set by
+// earlier lines or the built-in rules; unspecified resets it.
+type attrRule struct {
+	pattern   string
+	generated *bool // linguist-generated
+	vendored  *bool // linguist-vendored
+	noDiff    *bool // -diff / binary
+}
+
+// ParseAttributes reads the linguist-generated, linguist-vendored and diff
+// attributes from a .gitattributes file. Other attributes, macros other
+// than binary, and negative patterns are ignored.
+func ParseAttributes(src string) Attributes {
+	var a Attributes
+	for _, line := range strings.Split(src, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "!") {
+			continue
+		}
+		r := attrRule{pattern: fields[0]}
+		for _, attr := range fields[1:] {
+			name, value, set := parseAttr(attr)
+			switch name {
+			case "linguist-generated":
+				r.generated = value
+			case "linguist-vendored":
+				r.vendored = value
+			case "diff":
+				if set {
+					r.noDiff = not(value)
+				} else {
+					r.noDiff = unspecified
+				}
+			case "binary":
+				r.noDiff = value
+			}
+		}
+		if r.generated != nil || r.vendored != nil || r.noDiff != nil {
+			a.rules = append(a.rules, r)
+		}
+	}
+	return a
+}
+
+// unspecified is the value of "!name": it undoes earlier lines.
+var unspecified = new(bool)
+
+// parseAttr decodes "name", "-name", "!name" and "name=value". value is
+// unspecified for "!name"; set is false then.
+func parseAttr(attr string) (name string, value *bool, set bool) {
+	yes, no := true, false
+	switch {
+	case strings.HasPrefix(attr, "-"):
+		return attr[1:], &no, true
+	case strings.HasPrefix(attr, "!"):
+		return attr[1:], unspecified, false
+	}
+	name, v, ok := strings.Cut(attr, "=")
+	if ok && (v == "false" || v == "0") {
+		return name, &no, true
+	}
+	return name, &yes, true
+}
+
+func not(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	v := !*b
+	return &v
+}
+
+// lookup returns the final value of each attribute for p, nil when
+// unspecified; the last matching line wins, as in git.
+func (a Attributes) lookup(p string) (generated, vendored, noDiff *bool) {
+	for _, r := range a.rules {
+		if !matchAttrPattern(r.pattern, p) {
+			continue
+		}
+		if r.generated != nil {
+			generated = r.generated
+		}
+		if r.vendored != nil {
+			vendored = r.vendored
+		}
+		if r.noDiff != nil {
+			noDiff = r.noDiff
+		}
+	}
+	return specified(generated), specified(vendored), specified(noDiff)
+}
+
+// matchAttrPattern applies gitattributes pattern rules: a pattern without
+// a slash matches the file name at any depth, otherwise it is anchored at
+// the repo root; "**" matches any number of directories and a trailing
+// "/**" everything inside a directory.
+func matchAttrPattern(pattern, p string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	return matchGlob(pattern, p)
+}
+
+// matchGlob matches a slash-separated path against a glob that may use
+// "**" segments.
+func matchGlob(pattern, p string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(pat, segs []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			if len(pat) == 1 {
+				return len(segs) > 0
+			}
+			for i := range len(segs) + 1 {
+				if matchSegments(pat[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], segs[0]); !ok {
+			return false
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// FileFilter decides which changed files are generated or vendored. It
+// combines the repo's .gitattributes, the built-in patterns and
+// user-configured globs.
+type FileFilter struct {
+	Attrs     Attributes
+	Generated []string // extra globs treated as generated
+}
+
+// kind classifies p. Explicit .gitattributes values win over the
+// built-in patterns, so "linguist-generated=false" brings a file back.
+func (f FileFilter) kind(p string) int {
+	generated, vendored, noDiff := f.Attrs.lookup(p)
+	switch {
+	case isTrue(generated):
+		return kindGenerated
+	case isTrue(vendored):
+		return kindVendored
+	case isTrue(noDiff):
+		return kindNoDiff
+	}
+	for _, g := range f.Generated {
+		if matchAttrPattern(g, p) {
+			return kindGenerated
+		}
+	}
+
+	k := classify(p)
+	if k == kindGenerated && generated != nil || k == kindVendored && vendored != nil {
+		return contentKind(p)
+	}
+	return k
+}
+
+func specified(b *bool) *bool {
+	if b == unspecified {
+		return nil
+	}
+	return b
+}
+
+func isTrue(b *bool) bool { return b != nil && *b }
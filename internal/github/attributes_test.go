@@ -0,0 +1,79 @@
+package github
+
+import "testing"
+
+func TestMatchAttrPattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		// 슬래시가 없으면 어느 깊이의 파일 이름과도 맞는다
+		{"*.pb.go", "api/v1/x.pb.go", true},
+		{"*.pb.go", "x.pb.go.txt", false},
+		{"go.sum", "tools/go.sum", true},
+		// 슬래시가 있으면 루트에 고정된다
+		{"api/gen/*.go", "api/gen/x.go", true},
+		{"api/gen/*.go", "src/api/gen/x.go", false},
+		{"/docs/*.md", "docs/a.md", true},
+		{"/docs/*.md", "docs/sub/a.md", false},
+		// **
+		{"api/gen/**", "api/gen/a/b.go", true},
+		{"api/gen/**", "api/gen", false},
+		{"**/testdata/*", "a/b/testdata/x.json", true},
+		{"**/testdata/*", "testdata/x.json", true},
+		{"web/**/*.min.js", "web/a/b/c.min.js", true},
+		{"web/**/*.min.js", "web/c.min.js", true},
+	}
+	for _, tt := range tests {
+		if got := matchAttrPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchAttrPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFileFilterKind(t *testing.T) {
+	filter := FileFilter{
+		Attrs: ParseAttributes(`# comment
+api/gen/** linguist-generated
+*.golden -diff
+keep.golden !diff
+assets/*.svg binary
+third_party/ours/** linguist-vendored=false
+dist/keep.js linguist-generated=false
+docs/** linguist-generated
+docs/README.md !linguist-generated
+!ignored.go linguist-generated
+legacy/** linguist-vendored
+legacy/main.go linguist-vendored=0
+`),
+		Generated: []string{"schema/out/**"},
+	}
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"api/gen/client.go", kindGenerated},
+		{"testdata/out.golden", kindNoDiff},
+		{"testdata/keep.golden", kindTest},
+		{"assets/logo.svg", kindNoDiff},
+		{"schema/out/types.go", kindGenerated},
+		// gitattributes의 명시적 false는 기본 패턴을 이긴다
+		{"third_party/ours/lib.go", kindSource},
+		{"third_party/theirs/lib.go", kindVendored},
+		{"dist/keep.js", kindSource},
+		{"dist/bundle.js", kindGenerated},
+		// 나중 줄이 이기고, !는 앞의 값을 지운다
+		{"docs/guide.md", kindGenerated},
+		{"docs/README.md", kindDocs},
+		{"legacy/util.go", kindVendored},
+		{"legacy/main.go", kindSource},
+		// 부정 패턴 줄은 무시한다
+		{"ignored.go", kindSource},
+		{"cmd/main.go", kindSource},
+	}
+	for _, tt := range tests {
+		if got := filter.kind(tt.path); got != tt.want {
+			t.Errorf("kind(%q) = %s, want %s", tt.path, kindNames[got], kindNames[tt.want])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultBots are comment authors dropped unless configured otherwise.
+var DefaultBots = []string{"coderabbitai", "snyk-io-us", "dependabot", "github-actions", "codecov"}
+
+// How Collect treats PRs opened by bots (Limits.BotPRs).
+const (
+	BotPRsKeep  = "keep"  // summarize like any other PR
+	BotPRsSkip  = "skip"  // leave them out
+	BotPRsGroup = "group" // one "dependency updates" section listing them
+)
+
+// BotFilter decides which accounts are bots.
+type BotFilter struct {
+	Logins   []string // exact logins, case-insensitive
+	Suffix   bool     // any "name[bot]" login or GitHub App account
+	Patterns []*regexp.Regexp
+}
+
+// IsBot reports whether login belongs to a bot. "name[bot]" and the gh
+// CLI's "app/name" both match a "name" entry in Logins.
+func (f BotFilter) IsBot(login string) bool {
+	if f.Suffix && strings.HasSuffix(login, "[bot]") {
+		return true
+	}
+	name := strings.TrimPrefix(strings.TrimSuffix(login, "[bot]"), "app/")
+	for _, l := range f.Logins {
+		if strings.EqualFold(name, l) {
+			return true
+		}
+	}
+	for _, re := range f.Patterns {
+		if re.MatchString(login) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBotPR reports whether pr was opened by a bot.
+func (f BotFilter) IsBotPR(pr PR) bool {
+	if f.Suffix && pr.Author.Typename == "Bot" {
+		return true
+	}
+	return f.IsBot(pr.Author.Login)
+}
+
+// dependencyUpdates renders bot PRs as a single chunk, one line per PR.
+func dependencyUpdates(prs []PR) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Dependency updates (%d PRs by bots)\n", len(prs))
+	for _, pr := range prs {
+		fmt.Fprintf(&b, "- #%d %s (%s)\n", pr.Number, pr.Title, pr.Author.Login)
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package github
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestBotFilterIsBot(t *testing.T) {
+	logins := BotFilter{Logins: []string{"dependabot", "CodeCov"}}
+	suffix := BotFilter{Suffix: true}
+	patterns := BotFilter{Patterns: []*regexp.Regexp{regexp.MustCompile(`^ci-`), regexp.MustCompile(`-bot$`)}}
+	tests := []struct {
+		name   string
+		filter BotFilter
+		login  string
+		want   bool
+	}{
+		{"exact login", logins, "dependabot", true},
+		{"case-insensitive", logins, "codecov", true},
+		{"[bot] form of a login", logins, "dependabot[bot]", true},
+		{"gh app/ form of a login", logins, "app/dependabot", true},
+		{"other login", logins, "alice", false},
+		{"login prefix only", logins, "dependabot-fan", false},
+		{"[bot] suffix", suffix, "renovate[bot]", true},
+		{"[bot] suffix off", logins, "renovate[bot]", false},
+		{"no suffix", suffix, "alice", false},
+		{"pattern prefix", patterns, "ci-runner", true},
+		{"pattern suffix", patterns, "deploy-bot", true},
+		{"pattern miss", patterns, "robot", false},
+		{"empty filter", BotFilter{}, "dependabot", false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.IsBot(tt.login); got != tt.want {
+			t.Errorf("%s: IsBot(%q) = %v, want %v", tt.name, tt.login, got, tt.want)
+		}
+	}
+}
+
+func TestBotFilterIsBotPR(t *testing.T) {
+	pr := func(login, typename string) PR {
+		var p PR
+		p.Author.Login, p.Author.Typename = login, typename
+		return p
+	}
+	tests := []struct {
+		name   string
+		filter BotFilter
+		pr     PR
+		want   bool
+	}{
+		{"GraphQL Bot type", BotFilter{Suffix: true}, pr("renovate", "Bot"), true},
+		{"bot type without suffix rule", BotFilter{}, pr("renovate", "Bot"), false},
+		{"listed login", BotFilter{Logins: []string{"renovate"}}, pr("renovate", "User"), true},
+		{"user", BotFilter{Suffix: true}, pr("alice", "User"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.IsBotPR(tt.pr); got != tt.want {
+			t.Errorf("%s: IsBotPR = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
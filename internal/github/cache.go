@@ -0,0 +1,158 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache stores fetched PR artifacts (diffs, comments, ...) on disk. Entries
+// are keyed by repo, PR number and updatedAt, so a PR that changes after it
+// was cached is simply fetched again. A nil *Cache disables caching.
+type Cache struct {
+	Dir string
+
+	// Refresh skips reads but still writes, re-populating the cache.
+	Refresh bool
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/pr-news (or the OS equivalent).
+func DefaultCacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "pr-news")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "pr-news")
+	}
+	return filepath.Join(os.TempDir(), "pr-news-cache")
+}
+
+// path: <dir>/<owner>/<name>/<number>-<updatedAt>-<kind>.json
+func (c *Cache) path(repo string, pr PR, kind string) string {
+	name := fmt.Sprintf("%d-%d-%s.json", pr.Number, pr.UpdatedAt.Unix(), kind)
+	return filepath.Join(c.Dir, filepath.FromSlash(repo), name)
+}
+
+// Load decodes the cached kind entry for pr into v. It reports false on a
+// miss, when refreshing, or when the cache is disabled.
+func (c *Cache) Load(repo string, pr PR, kind string, v any) bool {
+	if c == nil || c.Refresh {
+		return false
+	}
+	data, err := os.ReadFile(c.path(repo, pr, kind))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Has reports whether Load would find a kind entry for pr.
+func (c *Cache) Has(repo string, pr PR, kind string) bool {
+	if c == nil || c.Refresh {
+		return false
+	}
+	_, err := os.Stat(c.path(repo, pr, kind))
+	return err == nil
+}
+
+// Store writes v as the kind entry for pr. Failures are ignored; the cache
+// is best-effort.
+func (c *Cache) Store(repo string, pr PR, kind string, v any) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	p := c.path(repo, pr, kind)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return
+	}
+	// 동시 수집 중 부분 파일을 읽지 않도록 rename으로 교체
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, p)
+}
+
+// Prune removes entries older than maxAge and entries superseded by a newer
+// updatedAt of the same PR. It returns the number of files removed.
+func (c *Cache) Prune(maxAge time.Duration) (int, error) {
+	type entry struct {
+		path    string
+		updated int64
+	}
+	latest := make(map[string][]entry) // dir/number-kind → entries
+	removed := 0
+	cutoff := time.Now().Add(-maxAge)
+
+	err := filepath.WalkDir(c.Dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if maxAge > 0 && info.ModTime().Before(cutoff) {
+			if os.Remove(p) == nil {
+				removed++
+			}
+			return nil
+		}
+		number, updated, kind, ok := parseCacheName(d.Name())
+		if !ok {
+			return nil
+		}
+		key := filepath.Join(filepath.Dir(p), number+"-"+kind)
+		latest[key] = append(latest[key], entry{p, updated})
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+
+	for _, entries := range latest {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].updated > entries[j].updated })
+		for _, e := range entries[1:] {
+			if os.Remove(e.path) == nil {
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+// Clear deletes the whole cache directory.
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.Dir)
+}
+
+func parseCacheName(name string) (number string, updated int64, kind string, ok bool) {
+	base, found := strings.CutSuffix(name, ".json")
+	if !found {
+		return "", 0, "", false
+	}
+	parts := strings.SplitN(base, "-", 3)
+	if len(parts) != 3 {
+		return "", 0, "", false
+	}
+	updated, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, "", false
+	}
+	return parts[0], updated, parts[2], true
+}
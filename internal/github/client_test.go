@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// fakeClient is a Client backed by maps; calls are counted per method.
+type fakeClient struct {
+	mu       sync.Mutex
+	calls    map[string]int
+	files    map[string]string // repo/path → contents
+	fileErr  error             // returned by GetFile when set
+	diffs    map[int]string
+	diffErr  map[int]error
+	prFiles  map[int][]string
+	filesErr map[int]error
+	comments map[int][]Comment
+}
+
+var errNotFaked = errors.New("not faked")
+
+func (f *fakeClient) count(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[method]++
+}
+
+func (f *fakeClient) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeClient) ListRepos(context.Context, int) ([]string, bool, error) {
+	return nil, false, errNotFaked
+}
+
+func (f *fakeClient) ListMergedPRs(context.Context, string, time.Time, string, int) ([]PR, int, error) {
+	return nil, 0, errNotFaked
+}
+
+func (f *fakeClient) Viewer(context.Context) (Viewer, error) { return Viewer{}, errNotFaked }
+
+func (f *fakeClient) RateLimits(context.Context) ([]RateLimit, error) { return nil, errNotFaked }
+
+func (f *fakeClient) OnRateLimit(func(RateLimitWait)) {}
+
+func (f *fakeClient) GetPRDiff(_ context.Context, _ string, number int) (string, error) {
+	f.count("GetPRDiff")
+	return f.diffs[number], f.diffErr[number]
+}
+
+func (f *fakeClient) ListComments(_ context.Context, _ string, number int) ([]Comment, error) {
+	f.count("ListComments")
+	return f.comments[number], nil
+}
+
+func (f *fakeClient) ListReviews(context.Context, string, int) (Reviews, error) {
+	f.count("ListReviews")
+	return Reviews{}, nil
+}
+
+func (f *fakeClient) ListFiles(_ context.Context, _ string, number int) ([]string, error) {
+	f.count("ListFiles")
+	return f.prFiles[number], f.filesErr[number]
+}
+
+func (f *fakeClient) GetFile(_ context.Context, repo, path, _ string) (string, error) {
+	f.count("GetFile")
+	time.Sleep(time.Millisecond) // 동시 호출이 겹치도록
+	if f.fileErr != nil {
+		return "", f.fileErr
+	}
+	return f.files[repo+"/"+path], nil
+}
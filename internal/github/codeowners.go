@@ -0,0 +1,241 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Owner modes for Limits.OwnerMode.
+const (
+	OwnerModeFilter = "filter" // only PRs touching the owner's files
+	OwnerModeGroup  = "group"  // every PR, plus a per-owner PR index
+)
+
+// codeOwnersPaths are the locations GitHub reads CODEOWNERS from, in order.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwners is a parsed CODEOWNERS file.
+type CodeOwners struct {
+	rules []ownerRule
+}
+
+type ownerRule struct {
+	pattern string
+	owners  []string // may be empty: the path has no owner
+}
+
+// ParseCodeOwners reads a CODEOWNERS file. Owners are kept as written
+// (@user, @org/team or an email).
+func ParseCodeOwners(src string) CodeOwners {
+	var o CodeOwners
+	for _, line := range strings.Split(src, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		o.rules = append(o.rules, ownerRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return o
+}
+
+// Owners returns the owners of p; the last matching line wins.
+func (o CodeOwners) Owners(p string) []string {
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if matchOwnerPattern(o.rules[i].pattern, p) {
+			return o.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// matchOwnerPattern applies CODEOWNERS rules: a leading or inner "/"
+// anchors the pattern at the root, otherwise it matches at any depth. A
+// trailing "/" matches directories only and covers everything below them,
+// as does a literal path such as "/apps/github"; a wildcard in the last
+// segment ("docs/*") matches direct children only.
+func matchOwnerPattern(pattern, p string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.Trim(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	pat := strings.Split(pattern, "/")
+	segs := strings.Split(p, "/")
+	// 디렉터리 아래 전체를 덮는 패턴인지
+	covers := dirOnly || !strings.ContainsAny(pat[len(pat)-1], "*?[")
+
+	starts := []int{0}
+	if !anchored {
+		starts = make([]int, len(segs))
+		for i := range starts {
+			starts[i] = i
+		}
+	}
+	for _, s := range starts {
+		for end := s + 1; end <= len(segs); end++ {
+			if end < len(segs) && !covers {
+				continue
+			}
+			if end == len(segs) && dirOnly {
+				break // 파일 자체는 디렉터리 패턴과 맞지 않는다
+			}
+			if matchSegments(pat, segs[s:end]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isOwner reports whether owner appears in owners, ignoring case and a
+// missing "@".
+func isOwner(owner string, owners []string) bool {
+	owner = normalizeOwner(owner)
+	for _, o := range owners {
+		if normalizeOwner(o) == owner {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeOwner(o string) string {
+	if !strings.Contains(o, "@") {
+		o = "@" + o
+	}
+	return strings.ToLower(o)
+}
+
+// codeOwners loads the repo's CODEOWNERS from the default branch once per
+// Collector; the result, including a failure, is shared by every worker.
+// A load cut short by ctx is not kept, so a later run tries again. ok is
+// false when the repo has none.
+func (c *Collector) codeOwners(ctx context.Context, repo string) (owners CodeOwners, ok bool, err error) {
+	c.mu.Lock()
+	if c.owners == nil {
+		c.owners = map[string]*loadedOwners{}
+	}
+	o := c.owners[repo]
+	if o == nil {
+		o = &loadedOwners{}
+		c.owners[repo] = o
+	}
+	c.mu.Unlock()
+
+	// 다른 레포의 조회를 막지 않도록 레포별 잠금만 잡는다
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done {
+		return o.owners, o.found, o.err
+	}
+	for _, path := range codeOwnersPaths {
+		src, err := c.Client.GetFile(ctx, repo, path, "")
+		if err != nil {
+			if ctx.Err() != nil {
+				return CodeOwners{}, false, err
+			}
+			o.done, o.err = true, err
+			return CodeOwners{}, false, err
+		}
+		if src != "" {
+			o.done, o.owners, o.found = true, ParseCodeOwners(src), true
+			return o.owners, true, nil
+		}
+	}
+	o.done = true
+	return CodeOwners{}, false, nil
+}
+
+type loadedOwners struct {
+	mu     sync.Mutex
+	done   bool
+	owners CodeOwners
+	found  bool
+	err    error
+}
+
+// OwnerIndex renders the "PRs by owner" section of group mode: each owner
+// of the PRs' changed files with the PRs touching them, Limits.Owner
+// first. It returns "" when the repo has no CODEOWNERS.
+func (c *Collector) OwnerIndex(ctx context.Context, repo string, prs []PR) (string, error) {
+	co, ok, err := c.codeOwners(ctx, repo)
+	if err != nil || !ok {
+		return "", err
+	}
+	const unowned = "(오너 없음)"
+	byOwner := map[string][]PR{}
+	for _, pr := range prs {
+		files, err := c.files(ctx, repo, pr)
+		if err != nil {
+			return "", err
+		}
+		seen := map[string]bool{}
+		for _, f := range files {
+			owners := co.Owners(f)
+			if len(owners) == 0 {
+				owners = []string{unowned}
+			}
+			for _, o := range owners {
+				if !seen[o] {
+					seen[o] = true
+					byOwner[o] = append(byOwner[o], pr)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(byOwner))
+	for o := range byOwner {
+		names = append(names, o)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		switch {
+		case c.Limits.Owner != "" && isOwner(c.Limits.Owner, []string{a}) != isOwner(c.Limits.Owner, []string{b}):
+			return isOwner(c.Limits.Owner, []string{a})
+		case (a == unowned) != (b == unowned):
+			return b == unowned
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+
+	var b strings.Builder
+	b.WriteString("## 👥 오너별 PR\n")
+	for _, o := range names {
+		refs := make([]string, len(byOwner[o]))
+		for i, pr := range byOwner[o] {
+			refs[i] = fmt.Sprintf("#%d %s", pr.Number, pr.Title)
+		}
+		fmt.Fprintf(&b, "- **%s**: %s\n", o, strings.Join(refs, ", "))
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// ownersShown reports whether PR chunks carry an Owners line.
+func (c *Collector) ownersShown() bool {
+	return c.Limits.Owner != "" || c.Limits.OwnerMode == OwnerModeGroup
+}
+
+// prOwners lists the owners of a PR's changed files.
+func (c *Collector) prOwners(ctx context.Context, repo string, files []FileDiff) string {
+	co, ok, err := c.codeOwners(ctx, repo)
+	if err != nil || !ok {
+		return ""
+	}
+	var owners []string
+	seen := map[string]bool{}
+	for _, f := range files {
+		for _, o := range co.Owners(f.Path) {
+			if !seen[o] {
+				seen[o] = true
+				owners = append(owners, o)
+			}
+		}
+	}
+	return strings.Join(owners, ", ")
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestMatchOwnerPattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		// 루트 고정
+		{"/README.md", "README.md", true},
+		{"/README.md", "docs/README.md", false},
+		{"/docs/", "docs/guide.md", true},
+		{"/docs/", "pkg/docs/x", false},
+		{"docs/api", "docs/api/v1.md", true},
+		{"docs/api", "src/docs/api/v1.md", false},
+		{"/docs/", "docs/a/b.md", true},
+		{"/apps/github", "apps/github/x/y.go", true},
+		// 마지막 세그먼트의 와일드카드는 바로 아래 파일만
+		{"docs/*", "docs/a.md", true},
+		{"docs/*", "docs/a/b.md", false},
+		{"*.md", "docs/a.md", true},
+		{"/api/*.go", "api/x.go/y", false},
+		// 고정되지 않은 패턴은 어느 깊이에서나
+		{"README.md", "docs/README.md", true},
+		{"docs", "pkg/docs/x", true},
+		{"*.go", "cmd/main.go", true},
+		{"*.go", "main.go.txt", false},
+		// 디렉터리 전용
+		{"build/", "build/out.bin", true},
+		{"build/", "src/build/out.bin", true},
+		{"build/", "build", false},
+		// **
+		{"**/logs", "a/b/logs/x.log", true},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"docs/**/*.md", "docs/c.md", true},
+		{"docs/**/*.md", "src/docs/c.md", false},
+		{"/api/**", "api/v1/x.go", true},
+		{"/api/**", "api", false},
+		// 빈 패턴
+		{"/", "x", false},
+	}
+	for _, tt := range tests {
+		if got := matchOwnerPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchOwnerPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeOwnersLastMatchWins(t *testing.T) {
+	co := ParseCodeOwners(`# comment
+*           @org/all
+/docs/      @org/docs   # trailing comment
+/docs/api/
+*.go        @alice bob@example.com
+`)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@org/all"}},
+		{"docs/guide.md", []string{"@org/docs"}},
+		{"docs/api/v1.md", nil}, // 오너 없는 규칙
+		{"pkg/docs/x.txt", []string{"@org/all"}},
+		{"docs/main.go", []string{"@alice", "bob@example.com"}},
+	}
+	for _, tt := range tests {
+		if got := co.Owners(tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIsOwner(t *testing.T) {
+	owners := []string{"@Org/Backend", "alice"}
+	for _, o := range []string{"@org/backend", "org/backend", "@alice", "ALICE"} {
+		if !isOwner(o, owners) {
+			t.Errorf("isOwner(%q) = false", o)
+		}
+	}
+	if isOwner("@bob", owners) {
+		t.Error("isOwner(@bob) = true")
+	}
+}
+
+func TestCodeOwnersLoadedOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *fakeClient
+		found   bool
+		wantErr bool
+		calls   int
+	}{
+		{"github dir", &fakeClient{files: map[string]string{"o/r/.github/CODEOWNERS": "* @a"}}, true, false, 1},
+		{"docs dir", &fakeClient{files: map[string]string{"o/r/docs/CODEOWNERS": "* @a"}}, true, false, 3},
+		{"none", &fakeClient{}, false, false, 3},
+		{"error cached", &fakeClient{fileErr: errors.New("boom")}, false, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Collector{Client: tt.client}
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, found, err := c.codeOwners(context.Background(), "o/r")
+					if found != tt.found || (err != nil) != tt.wantErr {
+						t.Errorf("codeOwners = %v, %v; want found %v, error %v", found, err, tt.found, tt.wantErr)
+					}
+				}()
+			}
+			wg.Wait()
+			if n := tt.client.Calls("GetFile"); n != tt.calls {
+				t.Errorf("GetFile called %d times, want %d", n, tt.calls)
+			}
+		})
+	}
+}
+
+func TestCodeOwnersCancelledLoadNotCached(t *testing.T) {
+	client := &fakeClient{files: map[string]string{"o/r/.github/CODEOWNERS": "* @a"}}
+	c := &Collector{Client: client}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.fileErr = context.Canceled
+	if _, _, err := c.codeOwners(ctx, "o/r"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("codeOwners with a cancelled context = %v, want context.Canceled", err)
+	}
+
+	client.fileErr = nil
+	_, found, err := c.codeOwners(context.Background(), "o/r")
+	if !found || err != nil {
+		t.Errorf("codeOwners after a cancelled load = %v, %v; want found", found, err)
+	}
+}
@@ -0,0 +1,282 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultWorkers is the collection concurrency used when none is configured.
+const DefaultWorkers = 4
+
+// Collector gathers PR data for many PRs with a bounded worker pool.
+type Collector struct {
+	Client  Client
+	Workers int
+	Cache   *Cache // optional
+	Limits  Limits
+
+	mu     sync.Mutex
+	owners map[string]*loadedOwners // CODEOWNERS by repo
+}
+
+// Collect runs CollectPR for every PR and returns the results in the
+// same order as prs, along with every call that failed; a PR whose diff
+// or comments could not be fetched is still included without them. PRs
+// opened by bots are skipped or grouped into one trailing chunk when
+// Limits.BotPRs says so. progress, if non-nil, is called once per
+// finished PR with the running count; calls are serialized. It stops
+// early with ctx's error when ctx is done.
+func (c *Collector) Collect(ctx context.Context, repo string, prs []PR, progress func(done int, pr PR)) ([]string, Failures, error) {
+	prs, bots := c.splitBotPRs(prs)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	// 봇 PR은 수집할 것이 없으므로 바로 완료 처리
+	for _, pr := range bots {
+		if progress != nil {
+			done++
+			progress(done, pr)
+		}
+	}
+
+	workers := c.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	workers = min(workers, len(prs))
+
+	results := make([]string, len(prs))
+	failed := make([]Failures, len(prs))
+	jobs := make(chan int)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], failed[i] = c.CollectPR(ctx, repo, prs[i])
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, prs[i])
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+dispatch:
+	for i := range prs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if c.Limits.BotPRs == BotPRsGroup && len(bots) > 0 {
+		results = append(results, dependencyUpdates(bots))
+	}
+	return results, slices.Concat(failed...), nil
+}
+
+// splitBotPRs separates the bot PRs that Limits.BotPRs keeps out of
+// collection from the PRs to collect; with the default keep, bots is nil.
+func (c *Collector) splitBotPRs(prs []PR) (collect, bots []PR) {
+	if c.Limits.BotPRs != BotPRsSkip && c.Limits.BotPRs != BotPRsGroup {
+		return prs, nil
+	}
+	for _, pr := range prs {
+		if c.Limits.Bots.IsBotPR(pr) {
+			bots = append(bots, pr)
+		} else {
+			collect = append(collect, pr)
+		}
+	}
+	return collect, bots
+}
+
+// CollectPR gathers formatted data for a single PR. Parts that cannot be
+// fetched are left out, noted in the data so the model does not guess,
+// and returned as failures.
+func (c *Collector) CollectPR(ctx context.Context, repo string, pr PR) (string, Failures) {
+	var (
+		b      strings.Builder
+		failed Failures
+	)
+	fail := func(part string, err error) {
+		failed = append(failed, Failure{Repo: repo, PR: pr.Number, Part: part, Err: err})
+	}
+
+	fmt.Fprintf(&b, "## PR #%d: %s\n", pr.Number, pr.Title)
+	fmt.Fprintf(&b, "- Author: %s\n", pr.Author.Login)
+	fmt.Fprintf(&b, "- Merged: %s\n", pr.MergedAt.Format("2006-01-02"))
+
+	var files []FileDiff
+	diff, diffErr := c.diff(ctx, repo, pr)
+	tooLarge := errors.Is(diffErr, ErrDiffTooLarge)
+	switch {
+	case diffErr == nil:
+		files = ParseDiff(diff)
+	case tooLarge:
+		// GitHub이 diff를 주지 않는 PR은 큰 PR로 보고 파일 목록만 쓴다
+		if paths, err := c.files(ctx, repo, pr); err != nil {
+			fail(PartDiff, err)
+		} else {
+			files = pathsOnly(paths)
+		}
+	default:
+		fail(PartDiff, diffErr)
+	}
+	filter := FileFilter{Attrs: c.attributes(ctx, repo, pr), Generated: c.Limits.Generated}
+
+	if c.ownersShown() {
+		if owners := c.prOwners(ctx, repo, files); owners != "" {
+			fmt.Fprintf(&b, "- Owners: %s\n", owners)
+		}
+	}
+
+	additions, deletions, changed := pr.Additions, pr.Deletions, pr.ChangedFiles
+	scope := ""
+	if match, err := c.pathFilter(ctx, repo); err == nil && match != nil {
+		// 범위 밖 파일은 목록, diff, 통계에서 모두 뺀다
+		files = keepFiles(files, match)
+		changed = len(files)
+		if !tooLarge { // 파일별 라인 수를 모르면 PR 전체 값을 둔다
+			additions, deletions = 0, 0
+			for _, f := range files {
+				additions += f.Additions
+				deletions += f.Deletions
+			}
+		}
+		scope = fmt.Sprintf(" in scope, of %d", pr.ChangedFiles)
+	}
+
+	// 생성/vendor 파일은 따로 세고, 크기 판단에서도 뺀다
+	add, del, n := excluded(files, filter)
+	additions, deletions, changed = additions-add, deletions-del, changed-n
+	stats := fmt.Sprintf("+%d -%d (%d files%s)", additions, deletions, changed, scope)
+	if n > 0 {
+		stats += fmt.Sprintf("; generated/vendored: +%d -%d (%d files)", add, del, n)
+	}
+	fmt.Fprintf(&b, "- Stats: %s\n", stats)
+	fmt.Fprintf(&b, "- URL: %s\n", pr.URL)
+	fmt.Fprintf(&b, "\n### Description\n%s\n", pr.Body)
+
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "\n### Changed Files\n%s\n", fileList(files, filter))
+	}
+	if slices.ContainsFunc(failed, func(f Failure) bool { return f.Part == PartDiff }) {
+		b.WriteString("\n> Diff unavailable (fetch failed)\n")
+	} else if tooLarge || c.Limits.IsLarge(changed, additions+deletions) {
+		b.WriteString("\n> Large PR - showing summary only\n")
+	} else if excerpt := selectDiff(files, filter, c.Limits.fileDiffLines(), c.Limits.diffLines()); excerpt != "" {
+		fmt.Fprintf(&b, "\n### Code Changes (excerpt)\n```diff\n%s\n```\n", excerpt)
+	}
+
+	if !c.Limits.SkipComments {
+		if comments, err := c.comments(ctx, repo, pr); err != nil {
+			fail(PartComments, err)
+		} else if s := formatComments(comments, c.Limits.Bots); s != "" {
+			fmt.Fprintf(&b, "\n### Review Comments\n%s\n", s)
+		}
+		if reviews, err := c.reviews(ctx, repo, pr); err != nil {
+			fail(PartReviews, err)
+		} else {
+			verdicts, threads := formatReviews(reviews, c.Limits.Bots)
+			if verdicts != "" {
+				fmt.Fprintf(&b, "\n### Reviews\n%s\n", verdicts)
+			}
+			if threads != "" {
+				fmt.Fprintf(&b, "\n### Review Threads\n%s\n", threads)
+			}
+		}
+	}
+
+	var missing []string // diff는 위에서 따로 표시
+	for _, f := range failed {
+		if f.Part != PartDiff {
+			missing = append(missing, f.Part)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(&b, "\n> Could not fetch: %s\n", strings.Join(missing, ", "))
+	}
+	return b.String(), failed
+}
+
+// pathsOnly turns a file list into FileDiffs without diff lines, which
+// fileList shows without stats.
+func pathsOnly(paths []string) []FileDiff {
+	files := make([]FileDiff, len(paths))
+	for i, p := range paths {
+		files[i] = FileDiff{Path: p}
+	}
+	return files
+}
+
+// diff fetches the PR diff through the cache.
+func (c *Collector) diff(ctx context.Context, repo string, pr PR) (string, error) {
+	var diff string
+	if c.Cache.Load(repo, pr, "diff", &diff) {
+		return diff, nil
+	}
+	diff, err := c.Client.GetPRDiff(ctx, repo, pr.Number)
+	if err != nil {
+		return "", err
+	}
+	c.Cache.Store(repo, pr, "diff", diff)
+	return diff, nil
+}
+
+// comments fetches the PR conversation comments through the cache.
+func (c *Collector) comments(ctx context.Context, repo string, pr PR) ([]Comment, error) {
+	var comments []Comment
+	if c.Cache.Load(repo, pr, "comments", &comments) {
+		return comments, nil
+	}
+	comments, err := c.Client.ListComments(ctx, repo, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	c.Cache.Store(repo, pr, "comments", comments)
+	return comments, nil
+}
+
+// reviews fetches the PR review verdicts and threads through the cache.
+func (c *Collector) reviews(ctx context.Context, repo string, pr PR) (Reviews, error) {
+	var reviews Reviews
+	if c.Cache.Load(repo, pr, "reviews", &reviews) {
+		return reviews, nil
+	}
+	reviews, err := c.Client.ListReviews(ctx, repo, pr.Number)
+	if err != nil {
+		return Reviews{}, err
+	}
+	c.Cache.Store(repo, pr, "reviews", reviews)
+	return reviews, nil
+}
+
+// attributes reads the repo's root .gitattributes at the PR's base commit
+// through the cache. A missing or unreadable file yields no rules.
+func (c *Collector) attributes(ctx context.Context, repo string, pr PR) Attributes {
+	var src string
+	if !c.Cache.Load(repo, pr, "gitattributes", &src) {
+		var err error
+		if src, err = c.Client.GetFile(ctx, repo, ".gitattributes", pr.BaseRefOid); err != nil {
+			return Attributes{}
+		}
+		c.Cache.Store(repo, pr, "gitattributes", src)
+	}
+	return ParseAttributes(src)
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const smallDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-old
++new
+`
+
+func TestCollectPRDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   *fakeClient
+		want     []string
+		missing  []string
+		failures []string // Part of each recorded failure
+	}{
+		{
+			name:    "small",
+			client:  &fakeClient{diffs: map[int]string{1: smallDiff}},
+			want:    []string{"- main.go (+1 -1)", "### Code Changes (excerpt)"},
+			missing: []string{"Large PR", "Diff unavailable"},
+		},
+		{
+			name: "too large",
+			client: &fakeClient{
+				diffErr: map[int]error{1: fmt.Errorf("%w: Sorry, the diff exceeded the maximum number of files", ErrDiffTooLarge)},
+				prFiles: map[int][]string{1: {"a.go", "b.go"}},
+			},
+			want:    []string{"- a.go\n- b.go", "> Large PR - showing summary only"},
+			missing: []string{"Diff unavailable", "(+0 -0)"},
+		},
+		{
+			name: "too large, file list failed",
+			client: &fakeClient{
+				diffErr:  map[int]error{1: ErrDiffTooLarge},
+				filesErr: map[int]error{1: errors.New("boom")},
+			},
+			want:     []string{"> Diff unavailable (fetch failed)"},
+			failures: []string{PartDiff},
+		},
+		{
+			name:     "failed",
+			client:   &fakeClient{diffErr: map[int]error{1: errors.New("boom")}},
+			want:     []string{"> Diff unavailable (fetch failed)"},
+			missing:  []string{"Large PR"},
+			failures: []string{PartDiff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Collector{Client: tt.client}
+			out, failed := c.CollectPR(context.Background(), "o/r", PR{Number: 1, Title: "t", ChangedFiles: 2})
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("output lacks %q:\n%s", w, out)
+				}
+			}
+			for _, m := range tt.missing {
+				if strings.Contains(out, m) {
+					t.Errorf("output has %q:\n%s", m, out)
+				}
+			}
+			var parts []string
+			for _, f := range failed {
+				parts = append(parts, f.Part)
+			}
+			if strings.Join(parts, ",") != strings.Join(tt.failures, ",") {
+				t.Errorf("failures = %v, want %v", parts, tt.failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package github
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// FileDiff is one file's section of a unified diff.
+type FileDiff struct {
+	Path      string // new path; the old path for deletions
+	OldPath   string // set for renames
+	Status    string // added, deleted, renamed, modified
+	Additions int
+	Deletions int
+	Binary    bool
+	Lines     []string // the section, starting with its "diff --git" line
+}
+
+// ParseDiff splits a unified diff (as returned by GetPRDiff) per file.
+func ParseDiff(diff string) []FileDiff {
+	var (
+		files  []FileDiff
+		cur    *FileDiff
+		inHunk bool // 헝크 안에서는 "--- "도 삭제된 줄이다
+	)
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, FileDiff{Status: "modified", Path: gitHeaderPath(line)})
+			cur = &files[len(files)-1]
+			inHunk = false
+		}
+		if cur == nil {
+			continue // 첫 파일 헤더 앞의 내용
+		}
+		cur.Lines = append(cur.Lines, line)
+
+		if inHunk {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				cur.Additions++
+			case strings.HasPrefix(line, "-"):
+				cur.Deletions++
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case strings.HasPrefix(line, "+++ b/"):
+			cur.Path = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Status = "deleted"
+		case strings.HasPrefix(line, "rename from "):
+			cur.Status = "renamed"
+			cur.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			cur.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+			cur.Binary = true
+		}
+	}
+	return files
+}
+
+// gitHeaderPath extracts the b/ path from "diff --git a/x b/x".
+func gitHeaderPath(line string) string {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return rest[i+3:]
+	}
+	return rest
+}
+
+// File kinds, in the order their diffs are shown. Kinds from kindLockfile
+// on are listed in the changed-file list but their diffs are omitted.
+const (
+	kindAPI = iota
+	kindSchema
+	kindSource
+	kindConfig
+	kindTest
+	kindDocs
+	kindOther
+	kindLockfile
+	kindGenerated
+	kindVendored
+	kindSnapshot
+	kindNoDiff // "-diff" in .gitattributes
+)
+
+var kindNames = [...]string{"api", "schema", "source", "config", "test", "docs", "other",
+	"lockfile", "generated", "vendored", "snapshot", "no-diff"}
+
+func skipKind(kind int) bool { return kind >= kindLockfile }
+
+var lockfiles = map[string]bool{
+	"package-lock.json": true, "npm-shrinkwrap.json": true, "yarn.lock": true,
+	"pnpm-lock.yaml": true, "bun.lockb": true, "go.sum": true, "Cargo.lock": true,
+	"Gemfile.lock": true, "poetry.lock": true, "Pipfile.lock": true, "uv.lock": true,
+	"composer.lock": true, "mix.lock": true, "pubspec.lock": true, "Podfile.lock": true,
+	"packages.lock.json": true, "flake.lock": true,
+}
+
+var sourceExts = map[string]bool{
+	".go": true, ".rs": true, ".py": true, ".rb": true, ".java": true, ".kt": true,
+	".scala": true, ".swift": true, ".c": true, ".h": true, ".cc": true, ".cpp": true,
+	".hpp": true, ".cs": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".mjs": true, ".vue": true, ".svelte": true, ".php": true, ".ex": true, ".exs": true,
+	".erl": true, ".hs": true, ".ml": true, ".clj": true, ".dart": true, ".lua": true,
+	".sh": true, ".bash": true, ".zig": true,
+}
+
+var configExts = map[string]bool{
+	".yaml": true, ".yml": true, ".toml": true, ".json": true, ".ini": true,
+	".conf": true, ".cfg": true, ".env": true, ".tf": true, ".hcl": true, ".xml": true,
+	".gradle": true,
+}
+
+var configNames = map[string]bool{
+	"Dockerfile": true, "Makefile": true, "go.mod": true, "package.json": true,
+	"Cargo.toml": true, "pyproject.toml": true, "Gemfile": true, "CMakeLists.txt": true,
+	"Jenkinsfile": true, "Procfile": true,
+}
+
+// classify guesses a file's kind from its path with the built-in patterns.
+func classify(p string) int {
+	base := path.Base(p)
+	ext := strings.ToLower(path.Ext(base))
+	dirs := "/" + path.Dir(p) + "/"
+
+	switch {
+	case lockfiles[base] || ext == ".lock":
+		return kindLockfile
+	case hasDir(dirs, "vendor", "node_modules", "third_party", "third-party", "bower_components"):
+		return kindVendored
+	case ext == ".snap" || hasDir(dirs, "__snapshots__"):
+		return kindSnapshot
+	case isGenerated(base, ext) || hasDir(dirs, "dist", "generated", "__generated__"):
+		return kindGenerated
+	}
+	return contentKind(p)
+}
+
+// contentKind ranks a file that is neither generated nor vendored.
+func contentKind(p string) int {
+	base := path.Base(p)
+	ext := strings.ToLower(path.Ext(base))
+	dirs := "/" + path.Dir(p) + "/"
+
+	switch {
+	case ext == ".proto" || ext == ".graphql" || ext == ".gql" || ext == ".thrift" ||
+		strings.HasPrefix(base, "openapi.") || strings.HasPrefix(base, "swagger."):
+		return kindAPI
+	case ext == ".sql" || ext == ".prisma" || hasDir(dirs, "migrations", "migrate") ||
+		strings.HasPrefix(base, "schema."):
+		return kindSchema
+	case isTest(base, dirs):
+		return kindTest
+	case sourceExts[ext]:
+		return kindSource
+	case configNames[base] || configExts[ext] || strings.HasPrefix(dirs, "/.github/"):
+		return kindConfig
+	case ext == ".md" || ext == ".rst" || ext == ".txt" || ext == ".adoc" || hasDir(dirs, "docs", "doc"):
+		return kindDocs
+	}
+	return kindOther
+}
+
+func hasDir(dirs string, names ...string) bool {
+	for _, n := range names {
+		if strings.Contains(dirs, "/"+n+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func isGenerated(base, ext string) bool {
+	for _, suffix := range []string{".pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", ".pb.ts",
+		"_generated.go", ".gen.go", ".gen.ts", ".generated.ts", ".min.js", ".min.css", ".designer.cs"} {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return ext == ".map" || strings.HasPrefix(base, "zz_generated")
+}
+
+func isTest(base, dirs string) bool {
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	return strings.HasSuffix(stem, "_test") || strings.HasSuffix(stem, ".test") ||
+		strings.HasSuffix(stem, ".spec") || strings.HasPrefix(stem, "test_") ||
+		hasDir(dirs, "test", "tests", "__tests__", "spec", "testdata")
+}
+
+// selectDiff picks the most relevant file sections: files are ranked by
+// kind (API and schema first, lockfiles and generated code never), each
+// is cut to perFile lines, and selection stops at total lines. Files that
+// don't fit are named at the end.
+func selectDiff(files []FileDiff, filter FileFilter, perFile, total int) string {
+	kinds := make([]int, len(files))
+	order := make([]int, 0, len(files))
+	for i, f := range files {
+		kinds[i] = filter.kind(f.Path)
+		if !f.Binary && !skipKind(kinds[i]) {
+			order = append(order, i)
+		}
+	}
+	// 같은 종류 안에서는 변경이 많은 파일 먼저
+	sort.SliceStable(order, func(a, b int) bool {
+		fa, fb := files[order[a]], files[order[b]]
+		ka, kb := kinds[order[a]], kinds[order[b]]
+		if ka != kb {
+			return ka < kb
+		}
+		return fa.Additions+fa.Deletions > fb.Additions+fb.Deletions
+	})
+
+	var (
+		b       strings.Builder
+		used    int
+		omitted []string
+	)
+	for _, i := range order {
+		f := files[i]
+		budget := min(perFile, total-used)
+		if budget < min(len(f.Lines), 5) { // 헤더만 들어갈 자리는 쓰지 않는다
+			omitted = append(omitted, f.Path)
+			continue
+		}
+		lines := f.Lines
+		if len(lines) > budget {
+			lines = append(lines[:budget:budget], fmt.Sprintf("... (%d more lines in %s)", len(f.Lines)-budget, f.Path))
+		}
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteByte('\n')
+		used += min(len(f.Lines), budget)
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(&b, "... (diff omitted for %d more files: %s)\n", len(omitted), strings.Join(omitted, ", "))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// maxListedFiles caps the changed-file list for very large PRs.
+const maxListedFiles = 100
+
+// fileList renders one line per changed file with its stats, when its
+// diff is known, and, for files whose diff is never shown, the reason.
+func fileList(files []FileDiff, filter FileFilter) string {
+	var lines []string
+	for i, f := range files {
+		if i == maxListedFiles {
+			lines = append(lines, fmt.Sprintf("- ... %d more files", len(files)-i))
+			break
+		}
+		name := f.Path
+		if f.Status == "renamed" {
+			name = f.OldPath + " → " + f.Path
+		}
+		line := "- " + name
+		if f.Lines != nil { // diff 없이 경로만 아는 파일은 통계를 생략
+			line += fmt.Sprintf(" (+%d -%d)", f.Additions, f.Deletions)
+		}
+		var tags []string
+		if f.Status == "added" || f.Status == "deleted" {
+			tags = append(tags, f.Status)
+		}
+		if f.Binary {
+			tags = append(tags, "binary")
+		} else if kind := filter.kind(f.Path); skipKind(kind) {
+			tags = append(tags, kindNames[kind])
+		}
+		if len(tags) > 0 {
+			line += " [" + strings.Join(tags, ", ") + "]"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// excluded totals the files whose diffs are never shown.
+func excluded(files []FileDiff, filter FileFilter) (additions, deletions, n int) {
+	for _, f := range files {
+		if skipKind(filter.kind(f.Path)) {
+			additions += f.Additions
+			deletions += f.Deletions
+			n++
+		}
+	}
+	return additions, deletions, n
+}
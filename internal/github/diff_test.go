@@ -0,0 +1,167 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1..2 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+--- old comment
++++ new comment
+-x
+diff --git a/new.txt b/new.txt
+new file mode 100644
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/old name.go b/new name.go
+similarity index 90%
+rename from old name.go
+rename to new name.go
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+	files := ParseDiff(diff)
+	var got []string
+	for _, f := range files {
+		got = append(got, fmt.Sprintf("%s|%s|%s|+%d-%d|%v", f.Path, f.OldPath, f.Status, f.Additions, f.Deletions, f.Binary))
+	}
+	want := []string{
+		// 헝크 안의 "--- "와 "+++ "는 헤더가 아니라 변경된 줄이다
+		"main.go||modified|+1-2|false",
+		"new.txt||added|+1-0|false",
+		"gone.go||deleted|+0-1|false",
+		"new name.go|old name.go|renamed|+0-0|false",
+		"logo.png||modified|+0-0|true",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ParseDiff:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if n := len(files[0].Lines); n != 9 {
+		t.Errorf("main.go section has %d lines, want 9", n)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"api/v1/service.proto", kindAPI},
+		{"openapi.yaml", kindAPI},
+		{"db/migrations/001_init.up.sql", kindSchema},
+		{"prisma/schema.prisma", kindSchema},
+		{"internal/app/update.go", kindSource},
+		{"internal/app/update_test.go", kindTest},
+		{"web/src/__tests__/app.tsx", kindTest},
+		{"tests/test_api.py", kindTest},
+		{"config/app.yaml", kindConfig},
+		{"Dockerfile", kindConfig},
+		{".github/workflows/ci.txt", kindConfig},
+		{"docs/guide.md", kindDocs},
+		{"README.md", kindDocs},
+		{"assets/logo.svg", kindOther},
+		{"web/package-lock.json", kindLockfile},
+		{"go.sum", kindLockfile},
+		{"vendor/github.com/x/y.go", kindVendored},
+		{"web/node_modules/x/index.js", kindVendored},
+		{"ui/__snapshots__/a.snap", kindSnapshot},
+		{"api/v1/service.pb.go", kindGenerated},
+		{"web/dist/app.js", kindGenerated},
+		{"static/app.min.js", kindGenerated},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.path); got != tt.want {
+			t.Errorf("classify(%q) = %s, want %s", tt.path, kindNames[got], kindNames[tt.want])
+		}
+	}
+}
+
+// section builds a FileDiff with a header and n changed lines.
+func section(p string, n int) FileDiff {
+	lines := []string{"diff --git a/" + p + " b/" + p, "@@ -1 +1 @@"}
+	for i := range n {
+		lines = append(lines, fmt.Sprintf("+%s line %d", p, i))
+	}
+	return FileDiff{Path: p, Status: "modified", Additions: n, Lines: lines}
+}
+
+func TestSelectDiff(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          []FileDiff
+		perFile, total int
+		order          []string // files shown, in order
+		omitted        string   // the omitted-files note, if any
+		hidden         []string // files never shown
+	}{
+		{
+			name:    "ranked by kind, then by size",
+			files:   []FileDiff{section("README.md", 3), section("a.go", 3), section("b.go", 8), section("api.proto", 2), section("a_test.go", 3)},
+			perFile: 50, total: 500,
+			order: []string{"api.proto", "b.go", "a.go", "a_test.go", "README.md"},
+		},
+		{
+			name:    "lockfiles, generated and binary files skipped",
+			files:   []FileDiff{section("go.sum", 3), section("x.pb.go", 3), {Path: "logo.png", Binary: true, Lines: []string{"diff --git a/logo.png b/logo.png"}}, section("main.go", 3)},
+			perFile: 50, total: 500,
+			order:  []string{"main.go"},
+			hidden: []string{"go.sum", "x.pb.go", "logo.png"},
+		},
+		{
+			name:    "per-file cut",
+			files:   []FileDiff{section("main.go", 20)},
+			perFile: 10, total: 500,
+			order: []string{"main.go"},
+		},
+		{
+			name:    "total budget names the rest",
+			files:   []FileDiff{section("a.go", 20), section("b.go", 10), section("c.go", 5)},
+			perFile: 50, total: 24,
+			order:   []string{"a.go"},
+			omitted: "... (diff omitted for 2 more files: b.go, c.go)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := selectDiff(tt.files, FileFilter{}, tt.perFile, tt.total)
+			var order []string
+			for _, line := range strings.Split(out, "\n") {
+				if p, ok := strings.CutPrefix(line, "diff --git a/"); ok {
+					order = append(order, p[:strings.Index(p, " b/")])
+				}
+			}
+			if strings.Join(order, ",") != strings.Join(tt.order, ",") {
+				t.Errorf("order = %v, want %v\n%s", order, tt.order, out)
+			}
+			if tt.omitted != "" && !strings.HasSuffix(out, tt.omitted) {
+				t.Errorf("output lacks %q:\n%s", tt.omitted, out)
+			}
+			for _, h := range tt.hidden {
+				if strings.Contains(out, h) {
+					t.Errorf("output shows %s:\n%s", h, out)
+				}
+			}
+			if tt.perFile < len(tt.files[0].Lines) && len(tt.files) == 1 {
+				want := fmt.Sprintf("... (%d more lines in main.go)", len(tt.files[0].Lines)-tt.perFile)
+				if !strings.HasSuffix(out, want) {
+					t.Errorf("output lacks %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Parts of a PR whose fetch can fail without dropping the PR.
+const (
+	PartDiff     = "diff"
+	PartComments = "comments"
+	PartReviews  = "reviews"
+)
+
+// Failure is a call that failed while collecting one PR.
+type Failure struct {
+	Repo string
+	PR   int
+	Part string // PartDiff, PartComments or PartReviews
+	Err  error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("%s#%d: %s: %v", f.Repo, f.PR, f.Part, f.Err)
+}
+
+func (f Failure) Unwrap() error { return f.Err }
+
+// Failures are the failed calls of a collection run, in PR order.
+type Failures []Failure
+
+// parts lists the parts that failed with the PR numbers affected, in a
+// fixed order.
+func (fs Failures) parts() (names []string, prs map[string][]int) {
+	prs = map[string][]int{}
+	for _, f := range fs {
+		if !slices.Contains(prs[f.Part], f.PR) {
+			prs[f.Part] = append(prs[f.Part], f.PR)
+		}
+	}
+	for _, p := range []string{PartDiff, PartComments, PartReviews} {
+		if len(prs[p]) > 0 {
+			names = append(names, p)
+		}
+	}
+	return names, prs
+}
+
+// Summary describes the failures of repo per part, e.g.
+// "org/app: 3 PRs had missing diffs (#12, #15, #20)".
+func (fs Failures) Summary(repo string) []string {
+	names, prs := fs.parts()
+	out := make([]string, 0, len(names))
+	for _, p := range names {
+		noun := "PRs"
+		if len(prs[p]) == 1 {
+			noun = "PR"
+		}
+		what := p
+		if p == PartDiff {
+			what = "diffs"
+		}
+		out = append(out, fmt.Sprintf("%s: %d %s had missing %s (%s)", repo, len(prs[p]), noun, what, prList(prs[p])))
+	}
+	return out
+}
+
+// partNames are the report's names for each part.
+var partNames = map[string]string{
+	PartDiff:     "diff",
+	PartComments: "코멘트",
+	PartReviews:  "리뷰",
+}
+
+// Note is the report's incomplete-data warning for repo, naming what the
+// summary could not see; "" if nothing failed.
+func (fs Failures) Note(repo string) string {
+	names, prs := fs.parts()
+	if len(names) == 0 {
+		return ""
+	}
+	missing := make([]string, len(names))
+	for i, p := range names {
+		missing[i] = fmt.Sprintf("%s %d개 (%s)", partNames[p], len(prs[p]), prList(prs[p]))
+	}
+	return fmt.Sprintf("%s: 불완전한 데이터 — 다음 PR 데이터를 가져오지 못해 요약에 반영되지 않았습니다: %s", repo, strings.Join(missing, ", "))
+}
+
+func prList(numbers []int) string {
+	s := make([]string, len(numbers))
+	for i, n := range numbers {
+		s[i] = fmt.Sprintf("#%d", n)
+	}
+	return strings.Join(s, ", ")
+}
+
+// SkippedNote is the report's warning for a repo left out of a digest
+// because its merged PRs could not be listed.
+func SkippedNote(repo string, err error) string {
+	return fmt.Sprintf("%s: 머지된 PR 목록을 가져오지 못해 보고서에서 제외했습니다 (%v)", repo, err)
+}
+
+// For returns the failures of repo.
+func (fs Failures) For(repo string) Failures {
+	var out Failures
+	for _, f := range fs {
+		if f.Repo == repo {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package github
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFailuresSummary(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name string
+		fs   Failures
+		want []string
+		note string
+	}{
+		{"none", nil, []string{}, ""},
+		{
+			name: "one diff",
+			fs:   Failures{{Repo: "o/r", PR: 12, Part: PartDiff, Err: boom}},
+			want: []string{"o/r: 1 PR had missing diffs (#12)"},
+			note: "o/r: 불완전한 데이터 — 다음 PR 데이터를 가져오지 못해 요약에 반영되지 않았습니다: diff 1개 (#12)",
+		},
+		{
+			name: "parts in fixed order, PRs deduplicated",
+			fs: Failures{
+				{Repo: "o/r", PR: 20, Part: PartReviews, Err: boom},
+				{Repo: "o/r", PR: 12, Part: PartComments, Err: boom},
+				{Repo: "o/r", PR: 15, Part: PartDiff, Err: boom},
+				{Repo: "o/r", PR: 20, Part: PartReviews, Err: boom},
+				{Repo: "o/r", PR: 21, Part: PartComments, Err: boom},
+			},
+			want: []string{
+				"o/r: 1 PR had missing diffs (#15)",
+				"o/r: 2 PRs had missing comments (#12, #21)",
+				"o/r: 1 PR had missing reviews (#20)",
+			},
+			note: "o/r: 불완전한 데이터 — 다음 PR 데이터를 가져오지 못해 요약에 반영되지 않았습니다: diff 1개 (#15), 코멘트 2개 (#12, #21), 리뷰 1개 (#20)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fs.Summary("o/r"); !slices.Equal(got, tt.want) {
+				t.Errorf("Summary = %q, want %q", got, tt.want)
+			}
+			if got := tt.fs.Note("o/r"); got != tt.note {
+				t.Errorf("Note = %q, want %q", got, tt.note)
+			}
+		})
+	}
+}
+
+func TestFailuresFor(t *testing.T) {
+	fs := Failures{
+		{Repo: "o/a", PR: 1, Part: PartDiff},
+		{Repo: "o/b", PR: 2, Part: PartDiff},
+		{Repo: "o/a", PR: 3, Part: PartReviews},
+	}
+	got := fs.For("o/a")
+	if len(got) != 2 || got[0].PR != 1 || got[1].PR != 3 {
+		t.Errorf("For(o/a) = %v", got)
+	}
+	if got := fs.For("o/c"); got != nil {
+		t.Errorf("For(o/c) = %v, want nil", got)
+	}
+}
+
+func TestFailureUnwrap(t *testing.T) {
+	boom := errors.New("boom")
+	f := Failure{Repo: "o/r", PR: 7, Part: PartComments, Err: boom}
+	if !errors.Is(f, boom) {
+		t.Error("Failure does not unwrap to its error")
+	}
+	if got, want := f.Error(), "o/r#7: comments: boom"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
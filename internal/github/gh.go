@@ -0,0 +1,371 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eddy/pr-news/internal/proc"
+	"github.com/eddy/pr-news/internal/retry"
+)
+
+// GhCLI is the fallback backend that shells out to the `gh` CLI.
+type GhCLI struct {
+	Retry retry.Policy // bounds each gh process and retries transient failures
+
+	limits *limiter
+}
+
+// NewGhCLI returns a gh backend that runs gh under policy.
+func NewGhCLI(policy retry.Policy) *GhCLI { return &GhCLI{Retry: policy, limits: newLimiter()} }
+
+// run runs gh with args and returns its stdout. Calls rejected by a rate
+// limit are retried like the API backend's: gh does not expose the
+// response headers, so primary limits wait for the reset reported by
+// /rate_limit and secondary ones back off.
+func (g GhCLI) run(ctx context.Context, args ...string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		out, err := g.exec(ctx, args...)
+		if ctx.Err() != nil {
+			return nil, ctx.Err() // gh가 취소로 종료됨
+		}
+		var failed *proc.Error
+		if !errors.As(err, &failed) || !errors.Is(err, proc.ErrRateLimited) {
+			return out, err
+		}
+		if attempt > maxRateLimitRetries {
+			return nil, &RateLimitError{Resource: ResourceCore}
+		}
+		w := RateLimitWait{Resource: ResourceCore, Attempt: attempt, Until: time.Now().Add(backoff(attempt))}
+		if slices.Contains(args, "graphql") {
+			w.Resource = ResourceGraphQL
+		}
+		if strings.Contains(failed.Stderr, "API rate limit exceeded") {
+			if q, ok := g.quota(ctx, w.Resource); ok && q.Remaining == 0 {
+				w.Until, w.Primary = q.Reset.Add(time.Second), true
+			}
+		}
+		if err := g.limits.pause(ctx, w); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// exec runs one gh command under g.Retry, rerunning it after transient
+// network and server errors.
+func (g GhCLI) exec(ctx context.Context, args ...string) ([]byte, error) {
+	var out []byte
+	err := retry.Do(ctx, g.Retry, "gh "+strings.Join(args[:min(len(args), 2)], " "), ghTransient, func(ctx context.Context) error {
+		var err error
+		out, err = ghCmd(args...).Output(ctx)
+		return err
+	})
+	return out, err
+}
+
+// ghTransient reports whether a failed gh run is worth repeating: gh
+// prints the HTTP status of failed API calls and Go's network errors.
+func ghTransient(err error) bool {
+	var failed *proc.Error
+	if !errors.As(err, &failed) {
+		return retry.Transient(err)
+	}
+	s := strings.ToLower(failed.Stderr)
+	for _, marker := range []string{"http 500", "http 502", "http 503", "http 504",
+		"connection reset", "connection refused", "i/o timeout", "tls handshake timeout",
+		"unexpected eof"} {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func ghCmd(args ...string) proc.Cmd {
+	return proc.Cmd{Name: "gh", Args: args, Classify: classifyGh}
+}
+
+// classifyGh recognizes gh's messages for the failures a user can fix.
+func classifyGh(e *proc.Error) {
+	s := strings.ToLower(e.Stderr)
+	switch {
+	case e.Kind == proc.ErrNotInstalled:
+		e.Hint = "Install the GitHub CLI (https://cli.github.com), or set GITHUB_TOKEN to use the API backend."
+	case strings.Contains(s, "saml"):
+		e.Kind = proc.ErrNotAuthenticated
+		e.Hint = "Authorize your GitHub token for the organization's SAML SSO, then run `gh auth refresh`."
+	case strings.Contains(s, "gh auth login") || strings.Contains(s, "not logged in") ||
+		strings.Contains(s, "http 401") || strings.Contains(s, "bad credentials"):
+		e.Kind = proc.ErrNotAuthenticated
+		e.Hint = "Run `gh auth login` (or set GITHUB_TOKEN), then check it with `gh auth status`."
+	case ghRateLimited(s):
+		e.Kind = proc.ErrRateLimited
+		e.Hint = "Wait for the limit to reset (see `gh api rate_limit`), or lower max_prs or days."
+	case strings.Contains(s, "could not resolve to a repository") || strings.Contains(s, "http 404"):
+		e.Kind = proc.ErrNotFound
+		e.Hint = "Check the owner/name spelling and that your account can see the repository (`gh auth status`)."
+	}
+}
+
+// ghRateLimited reports whether gh's stderr describes a rate limit
+// rejection.
+func ghRateLimited(stderr string) bool {
+	s := strings.ToLower(stderr)
+	return strings.Contains(s, "rate limit") || strings.Contains(s, "http 429") ||
+		strings.Contains(s, "submitted too quickly")
+}
+
+// quota reads resource's quota from /rate_limit, which does not count
+// against it.
+func (g GhCLI) quota(ctx context.Context, resource string) (RateLimit, bool) {
+	limits, err := g.RateLimits(ctx)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	for _, q := range limits {
+		if q.Resource == resource {
+			return q, true
+		}
+	}
+	return RateLimit{}, false
+}
+
+// Viewer reads the scopes from the response headers that
+// `gh api --include` prints before the body.
+func (g GhCLI) Viewer(ctx context.Context) (Viewer, error) {
+	out, err := g.run(ctx, "api", "--include", "user")
+	if err != nil {
+		return Viewer{}, fmt.Errorf("checking authentication: %w", err)
+	}
+	head, body, _ := strings.Cut(strings.ReplaceAll(string(out), "\r\n", "\n"), "\n\n")
+	header := http.Header{}
+	for _, line := range strings.Split(head, "\n")[1:] { // 첫 줄은 상태 줄
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			header.Add(strings.TrimSpace(k), strings.TrimSpace(v))
+		}
+	}
+	return parseViewer(header, []byte(body))
+}
+
+// GhVersion returns the first line of `gh --version`.
+func GhVersion(ctx context.Context) (string, error) {
+	out, err := ghCmd("--version").Output(ctx)
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line, nil
+}
+
+func (g GhCLI) OnRateLimit(f func(RateLimitWait)) { g.limits.setNotify(f) }
+
+func (GhCLI) RateLimits(ctx context.Context) ([]RateLimit, error) {
+	out, err := ghCmd("api", "rate_limit").Output(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading rate limits: %w", err)
+	}
+	var resp rateLimitResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("parsing rate limits: %w", err)
+	}
+	return resp.limits(), nil
+}
+
+func (g GhCLI) ListRepos(ctx context.Context, limit int) ([]string, bool, error) {
+	if limit <= 0 {
+		limit = DefaultMaxRepos
+	}
+	// limit+1을 요청해 잘렸는지 확인한다
+	own, err := g.repoList(ctx, "", limit)
+	if err != nil {
+		return nil, false, fmt.Errorf("listing repos: %w", err)
+	}
+	truncated := len(own) > limit
+	repos := make(map[string]bool)
+	for _, r := range own[:min(len(own), limit)] {
+		repos[r] = true
+	}
+
+	// Org repos
+	orgOut, _ := g.run(ctx, "api", "user/orgs", "--paginate", "--jq", ".[].login")
+	for _, org := range strings.Split(strings.TrimSpace(string(orgOut)), "\n") {
+		if org == "" {
+			continue
+		}
+		oRepos, _ := g.repoList(ctx, org, limit)
+		truncated = truncated || len(oRepos) > limit
+		for _, r := range oRepos[:min(len(oRepos), limit)] {
+			repos[r] = true
+		}
+	}
+
+	result := make([]string, 0, len(repos))
+	for r := range repos {
+		result = append(result, r)
+	}
+	sort.Strings(result)
+	return result, truncated, nil
+}
+
+// repoList lists up to limit+1 repos of owner ("" for the user),
+// most recently pushed first.
+func (g GhCLI) repoList(ctx context.Context, owner string, limit int) ([]string, error) {
+	args := []string{"repo", "list"}
+	if owner != "" {
+		args = append(args, owner)
+	}
+	args = append(args,
+		"--limit", fmt.Sprintf("%d", limit+1),
+		"--json", "nameWithOwner",
+		"-q", ".[].nameWithOwner",
+	)
+	out, err := g.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var repos []string
+	for _, r := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if r != "" {
+			repos = append(repos, r)
+		}
+	}
+	return repos, nil
+}
+
+func (g GhCLI) ListMergedPRs(ctx context.Context, repo string, since time.Time, baseBranch string, limit int) ([]PR, int, error) {
+	return pageMergedPRs(ctx, mergedPRsSearch(repo, since, baseBranch), limit, func(vars map[string]any, out *searchPage) error {
+		args := []string{"api", "graphql",
+			"-f", "query=" + mergedPRsQuery,
+			"-f", fmt.Sprintf("q=%s", vars["q"]),
+			"-F", fmt.Sprintf("first=%d", vars["first"]),
+		}
+		if after, ok := vars["after"]; ok {
+			args = append(args, "-f", fmt.Sprintf("after=%s", after))
+		}
+		data, err := g.run(ctx, args...)
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Data searchPage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("parsing PRs: %w", err)
+		}
+		*out = resp.Data
+		return nil
+	})
+}
+
+func (g GhCLI) GetPRDiff(ctx context.Context, repo string, number int) (string, error) {
+	out, err := g.run(ctx, "pr", "diff",
+		fmt.Sprintf("%d", number),
+		"--repo", repo,
+	)
+	var failed *proc.Error
+	if errors.As(err, &failed) && strings.Contains(strings.ToLower(failed.Stderr), "http 406") {
+		return "", fmt.Errorf("%w: %w", ErrDiffTooLarge, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (g GhCLI) ListFiles(ctx context.Context, repo string, number int) ([]string, error) {
+	out, err := g.run(ctx, "api", "--paginate",
+		fmt.Sprintf("repos/%s/pulls/%d/files?per_page=100", repo, number),
+		"--jq", ".[] | .filename, (.previous_filename // empty)",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing files: %w", err)
+	}
+	var files []string
+	for _, f := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (g GhCLI) GetFile(ctx context.Context, repo, path, ref string) (string, error) {
+	target := fmt.Sprintf("repos/%s/contents/%s", repo, path)
+	if ref != "" {
+		target += "?ref=" + url.QueryEscape(ref)
+	}
+	out, err := g.run(ctx, "api", target,
+		"-H", "Accept: application/vnd.github.raw",
+	)
+	if errors.Is(err, proc.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return string(out), nil
+}
+
+func (g GhCLI) ListComments(ctx context.Context, repo string, number int) ([]Comment, error) {
+	out, err := g.run(ctx, "pr", "view",
+		fmt.Sprintf("%d", number),
+		"--repo", repo,
+		"--json", "comments",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing comments: %w", err)
+	}
+
+	var resp struct {
+		Comments []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			AuthorAssociation string `json:"authorAssociation"`
+			Body              string `json:"body"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("parsing comments: %w", err)
+	}
+	comments := make([]Comment, 0, len(resp.Comments))
+	for _, c := range resp.Comments {
+		comments = append(comments, Comment{
+			Author:            c.Author.Login,
+			AuthorAssociation: c.AuthorAssociation,
+			Body:              c.Body,
+		})
+	}
+	return comments, nil
+}
+
+func (g GhCLI) ListReviews(ctx context.Context, repo string, number int) (Reviews, error) {
+	vars, err := reviewsVars(repo, number)
+	if err != nil {
+		return Reviews{}, err
+	}
+	out, err := g.run(ctx, "api", "graphql",
+		"-f", "query="+reviewsQuery,
+		"-f", fmt.Sprintf("owner=%s", vars["owner"]),
+		"-f", fmt.Sprintf("name=%s", vars["name"]),
+		"-F", fmt.Sprintf("number=%d", number),
+	)
+	if err != nil {
+		return Reviews{}, fmt.Errorf("listing reviews: %w", err)
+	}
+
+	var resp struct {
+		Data reviewsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return Reviews{}, fmt.Errorf("parsing reviews: %w", err)
+	}
+	return resp.Data.reviews(), nil
+}
@@ -1,11 +1,15 @@
 package github
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"os"
 	"strings"
 	"time"
+
+	"github.com/eddy/pr-news/internal/proc"
+	"github.com/eddy/pr-news/internal/retry"
 )
 
 type PR struct {
@@ -16,144 +20,220 @@ type PR struct {
 	Deletions    int       `json:"deletions"`
 	ChangedFiles int       `json:"changedFiles"`
 	MergedAt     time.Time `json:"mergedAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	BaseRefOid   string    `json:"baseRefOid"` // base branch commit at merge time
 	Author       struct {
-		Login string `json:"login"`
+		Login    string `json:"login"`
+		Typename string `json:"__typename"` // "Bot" for GitHub Apps
 	} `json:"author"`
 	URL string `json:"url"`
 }
 
+// Comment is a PR conversation (issue-style) comment.
+type Comment struct {
+	Author            string
+	AuthorAssociation string // OWNER, MEMBER, CONTRIBUTOR, NONE, ...
+	Body              string
+}
+
+// Client is a GitHub backend. Implementations: API (REST/GraphQL over HTTP)
+// and GhCLI (shells out to `gh`).
+type Client interface {
+	// ListRepos returns accessible repositories (personal + org), at most
+	// max per owner, most recently pushed first; truncated reports that an
+	// owner had more.
+	ListRepos(ctx context.Context, max int) (repos []string, truncated bool, err error)
+	// ListMergedPRs returns the max most recently merged PRs merged at or
+	// after since, newest merge first. total counts every matching PR, so total > len(prs) means
+	// the list was truncated.
+	ListMergedPRs(ctx context.Context, repo string, since time.Time, baseBranch string, max int) (prs []PR, total int, err error)
+	// Viewer returns the authenticated account and its token's scopes.
+	Viewer(ctx context.Context) (Viewer, error)
+	// RateLimits returns the remaining core and GraphQL API quotas.
+	RateLimits(ctx context.Context) ([]RateLimit, error)
+	// OnRateLimit installs a callback run before each pause forced by a
+	// rate limit; requests are retried after the pause. nil removes it.
+	OnRateLimit(func(RateLimitWait))
+	// GetPRDiff returns the full unified diff for a PR, or ErrDiffTooLarge
+	// when GitHub will not render it.
+	GetPRDiff(ctx context.Context, repo string, number int) (string, error)
+	// ListComments returns the conversation comments on a PR.
+	ListComments(ctx context.Context, repo string, number int) ([]Comment, error)
+	// ListReviews returns the review verdicts and inline review threads.
+	ListReviews(ctx context.Context, repo string, number int) (Reviews, error)
+	// ListFiles returns the paths a PR changes; renames list both paths.
+	ListFiles(ctx context.Context, repo string, number int) ([]string, error)
+	// GetFile returns a file's contents at ref (a commit, branch or "" for
+	// the default branch); "" without error if the file does not exist.
+	GetFile(ctx context.Context, repo, path, ref string) (string, error)
+}
+
+// ErrDiffTooLarge reports that GitHub refused to render a PR's diff
+// (HTTP 406) because it has too many files or lines.
+var ErrDiffTooLarge = errors.New("diff too large")
+
+// Backend names accepted by New.
 const (
-	ThresholdFiles   = 10
-	ThresholdChanges = 500
+	BackendAuto = "auto"
+	BackendAPI  = "api"
+	BackendGh   = "gh"
 )
 
-// ListRepos returns accessible repositories (personal + org).
-func ListRepos(limit int) ([]string, error) {
-	out, err := exec.Command("gh", "repo", "list",
-		"--limit", fmt.Sprintf("%d", limit),
-		"--json", "nameWithOwner",
-		"-q", ".[].nameWithOwner",
-	).Output()
-	if err != nil {
-		return nil, fmt.Errorf("listing repos: %w", err)
-	}
+// Config selects and configures a Client.
+type Config struct {
+	Backend string
+	BaseURL string // REST base URL; GitHub Enterprise uses https://HOST/api/v3
+	Token   string
 
-	repos := make(map[string]bool)
-	for _, r := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		if r != "" {
-			repos[r] = true
-		}
-	}
+	CallTimeout time.Duration // per request or gh process; 0 means none
+	Retries     int           // extra attempts after a transient failure
+}
 
-	// Org repos
-	orgOut, _ := exec.Command("gh", "api", "user/orgs", "--jq", ".[].login").Output()
-	for _, org := range strings.Split(strings.TrimSpace(string(orgOut)), "\n") {
-		if org == "" {
-			continue
+func (c Config) retryPolicy() retry.Policy {
+	return retry.Policy{Retries: c.Retries, Timeout: c.CallTimeout}
+}
+
+// New returns the Client for cfg.Backend. The auto backend uses the API when
+// a token is available (from cfg or `gh auth token`) and falls back to gh.
+func New(cfg Config) (Client, error) {
+	if cfg.Token == "" {
+		cfg.Token = envToken()
+	}
+	switch cfg.Backend {
+	case BackendGh:
+		return NewGhCLI(cfg.retryPolicy()), nil
+	case "", BackendAuto, BackendAPI:
+		token := cfg.Token
+		if token == "" {
+			token = ghAuthToken(cfg.BaseURL)
+		}
+		if token != "" {
+			api := NewAPI(cfg.BaseURL, token)
+			api.Retry = cfg.retryPolicy()
+			return api, nil
 		}
-		oRepos, _ := exec.Command("gh", "repo", "list", org,
-			"--limit", fmt.Sprintf("%d", limit),
-			"--json", "nameWithOwner",
-			"-q", ".[].nameWithOwner",
-		).Output()
-		for _, r := range strings.Split(strings.TrimSpace(string(oRepos)), "\n") {
-			if r != "" {
-				repos[r] = true
-			}
+		if cfg.Backend == BackendAPI {
+			return nil, fmt.Errorf("api backend requires GITHUB_TOKEN or `gh auth login`")
 		}
+		return NewGhCLI(cfg.retryPolicy()), nil
 	}
+	return nil, fmt.Errorf("unknown GitHub backend %q (want %s, %s or %s)",
+		cfg.Backend, BackendAuto, BackendAPI, BackendGh)
+}
 
-	result := make([]string, 0, len(repos))
-	for r := range repos {
-		result = append(result, r)
+// envToken returns GITHUB_TOKEN or GH_TOKEN.
+func envToken() string {
+	if t := os.Getenv("GITHUB_TOKEN"); t != "" {
+		return t
 	}
-	return result, nil
+	return os.Getenv("GH_TOKEN")
 }
 
-// ListMergedPRs returns merged PRs in the given date range.
-func ListMergedPRs(repo string, days int, baseBranch string) ([]PR, error) {
-	since := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
-	search := fmt.Sprintf("merged:>=%s", since)
-	if baseBranch != "" {
-		search += " base:" + baseBranch
+// ghAuthToken asks the gh CLI for its stored token; "" if unavailable.
+func ghAuthToken(baseURL string) string {
+	args := []string{"auth", "token"}
+	if host := hostOf(baseURL); host != "" && host != "api.github.com" {
+		args = append(args, "--hostname", host)
 	}
-
-	out, err := exec.Command("gh", "pr", "list",
-		"--repo", repo,
-		"--state", "merged",
-		"--search", search,
-		"--limit", "50",
-		"--json", "number,title,body,additions,deletions,changedFiles,mergedAt,author,url",
-	).Output()
+	out, err := proc.Cmd{Name: "gh", Args: args}.Output(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("listing PRs: %w", err)
+		return ""
 	}
+	return strings.TrimSpace(string(out))
+}
 
-	var prs []PR
-	if err := json.Unmarshal(out, &prs); err != nil {
-		return nil, fmt.Errorf("parsing PRs: %w", err)
-	}
-	return prs, nil
+// Defaults for Limits; they match the shell version's config.sh.
+const (
+	DefaultThresholdFiles   = 10
+	DefaultThresholdChanges = 500
+	DefaultDiffLines        = 500
+	DefaultFileDiffLines    = 150
+)
+
+// Limits bounds how much of each PR is collected. Zero values use the
+// defaults above.
+type Limits struct {
+	ThresholdFiles   int // PRs with more files show a summary only
+	ThresholdChanges int // PRs with more changed lines show a summary only
+	DiffLines        int // diff excerpt cap in lines
+	FileDiffLines    int // per-file cap within the excerpt
+	SkipComments     bool
+	Bots             BotFilter // comment authors to drop
+	Generated        []string  // extra globs whose diffs are never shown
+	Scope            Scope     // only PRs and files under these paths
+	Owner            string    // CODEOWNERS owner (@user or @org/team) to report on
+	OwnerMode        string    // OwnerModeFilter (default) or OwnerModeGroup
+	BotPRs           string    // BotPRsKeep (default), BotPRsSkip or BotPRsGroup
 }
 
-// IsLargePR returns true if the PR exceeds size thresholds.
-func IsLargePR(files, changes int) bool {
-	return files > ThresholdFiles || changes > ThresholdChanges
+// IsLarge returns true if the PR exceeds the size thresholds.
+func (l Limits) IsLarge(files, changes int) bool {
+	return files > orDefault(l.ThresholdFiles, DefaultThresholdFiles) ||
+		changes > orDefault(l.ThresholdChanges, DefaultThresholdChanges)
 }
 
-// GetPRDiff returns the diff for a PR (capped at maxLines).
-func GetPRDiff(repo string, number int, maxLines int) (string, error) {
-	out, err := exec.Command("gh", "pr", "diff",
-		fmt.Sprintf("%d", number),
-		"--repo", repo,
-	).Output()
-	if err != nil {
-		return "", err
+// ScopeLabel describes the path scope and owner filter for report
+// headers; "" when the whole repo is covered.
+func (l Limits) ScopeLabel() string {
+	var parts []string
+	if s := l.Scope.String(); s != "" {
+		parts = append(parts, s)
 	}
-	lines := strings.Split(string(out), "\n")
-	if len(lines) > maxLines {
-		lines = lines[:maxLines]
+	if l.Owner != "" && l.OwnerMode != OwnerModeGroup {
+		parts = append(parts, "owner "+l.Owner)
 	}
-	return strings.Join(lines, "\n"), nil
+	return strings.Join(parts, "; ")
 }
 
-// GetReviewComments returns review comments for a PR (bot-filtered).
-func GetReviewComments(repo string, number int) (string, error) {
-	out, err := exec.Command("gh", "pr", "view",
-		fmt.Sprintf("%d", number),
-		"--repo", repo,
-		"--json", "comments",
-		"--jq", `.comments[] | select(.authorAssociation != "NONE") | "- **\(.author.login)**: \(.body | split("\n")[0])"`,
-	).Output()
-	if err != nil {
-		return "", nil // non-fatal
-	}
-	return strings.TrimSpace(string(out)), nil
-}
+func (l Limits) diffLines() int { return orDefault(l.DiffLines, DefaultDiffLines) }
 
-// CollectPRData gathers formatted data for a single PR.
-func CollectPRData(repo string, pr PR) string {
-	var b strings.Builder
-	changes := pr.Additions + pr.Deletions
+func (l Limits) fileDiffLines() int { return orDefault(l.FileDiffLines, DefaultFileDiffLines) }
 
-	fmt.Fprintf(&b, "## PR #%d: %s\n", pr.Number, pr.Title)
-	fmt.Fprintf(&b, "- Author: %s\n", pr.Author.Login)
-	fmt.Fprintf(&b, "- Merged: %s\n", pr.MergedAt.Format("2006-01-02"))
-	fmt.Fprintf(&b, "- Stats: +%d -%d (%d files)\n", pr.Additions, pr.Deletions, pr.ChangedFiles)
-	fmt.Fprintf(&b, "- URL: %s\n", pr.URL)
-	fmt.Fprintf(&b, "\n### Description\n%s\n", pr.Body)
+func orDefault(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
 
-	if !IsLargePR(pr.ChangedFiles, changes) {
-		if diff, err := GetPRDiff(repo, pr.Number, 500); err == nil && diff != "" {
-			fmt.Fprintf(&b, "\n### Code Changes (excerpt)\n```diff\n%s\n```\n", diff)
+// formatComments renders non-anonymous comments as one bullet per comment,
+// skipping bots.
+func formatComments(comments []Comment, bots BotFilter) string {
+	var lines []string
+	for _, c := range comments {
+		if c.AuthorAssociation == "NONE" || bots.IsBot(c.Author) {
+			continue
 		}
-	} else {
-		b.WriteString("\n> Large PR - showing summary only\n")
+		lines = append(lines, fmt.Sprintf("- **%s**: %s", c.Author, excerpt(c.Body)))
 	}
+	return strings.Join(lines, "\n")
+}
 
-	if comments, err := GetReviewComments(repo, pr.Number); err == nil && comments != "" {
-		fmt.Fprintf(&b, "\n### Review Comments\n%s\n", comments)
+// DateRange returns the oldest and newest merge times among prs.
+func DateRange(prs []PR) (start, end time.Time) {
+	for i, pr := range prs {
+		if i == 0 || pr.MergedAt.Before(start) {
+			start = pr.MergedAt
+		}
+		if i == 0 || pr.MergedAt.After(end) {
+			end = pr.MergedAt
+		}
 	}
+	return start, end
+}
 
-	return b.String()
+// DaysAgo returns local midnight n days before today, the start of a
+// "last n days" window.
+func DaysAgo(n int) time.Time {
+	y, m, d := time.Now().AddDate(0, 0, -n).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// mergedSearch builds the search qualifiers shared by both backends.
+func mergedSearch(since time.Time, baseBranch string) string {
+	search := fmt.Sprintf("merged:>=%s", since.UTC().Format(time.RFC3339))
+	if baseBranch != "" {
+		search += " base:" + baseBranch
+	}
+	return search
 }
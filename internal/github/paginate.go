@@ -0,0 +1,126 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default caps for ListMergedPRs and ListRepos.
+const (
+	DefaultMaxPRs   = 500
+	DefaultMaxRepos = 100 // per owner (the user and each org)
+
+	// MaxSearchResults is GitHub's hard limit on search results.
+	MaxSearchResults = 1000
+)
+
+// searchPageSize is the GraphQL page size (GitHub's maximum).
+const searchPageSize = 100
+
+const mergedPRsQuery = `query($q: String!, $first: Int!, $after: String) {
+  search(query: $q, type: ISSUE, first: $first, after: $after) {
+    issueCount
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      ... on PullRequest {
+        number title body additions deletions changedFiles mergedAt updatedAt url baseRefOid
+        author { login __typename }
+      }
+    }
+  }
+}`
+
+// searchPage is the data of one mergedPRsQuery page.
+type searchPage struct {
+	Search struct {
+		IssueCount int `json:"issueCount"`
+		PageInfo   struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+		Nodes []PR `json:"nodes"`
+	} `json:"search"`
+}
+
+// mergedPRsSearch builds the search string for ListMergedPRs.
+func mergedPRsSearch(repo string, since time.Time, baseBranch string) string {
+	return fmt.Sprintf("repo:%s is:pr is:merged %s sort:created-desc", repo, mergedSearch(since, baseBranch))
+}
+
+// pageMergedPRs runs mergedPRsQuery page by page through query and
+// returns the limit most recently merged PRs, newest first. Search can
+// only sort by creation, so when more than limit PRs match every page (up
+// to MaxSearchResults) is read before the cap is applied; otherwise a
+// long-lived PR merged yesterday could be dropped. total is the search's
+// match count.
+func pageMergedPRs(ctx context.Context, q string, limit int, query func(vars map[string]any, out *searchPage) error) (prs []PR, total int, err error) {
+	limit = PRLimit(limit)
+	vars := map[string]any{"q": q, "first": searchPageSize}
+	for len(prs) < MaxSearchResults {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		var page searchPage
+		if err := query(vars, &page); err != nil {
+			return nil, 0, fmt.Errorf("listing PRs: %w", err)
+		}
+		prs = append(prs, page.Search.Nodes...)
+		total = page.Search.IssueCount
+		if !page.Search.PageInfo.HasNextPage || len(page.Search.Nodes) == 0 {
+			break
+		}
+		vars["after"] = page.Search.PageInfo.EndCursor
+	}
+	sort.SliceStable(prs, func(i, j int) bool { return prs[i].MergedAt.After(prs[j].MergedAt) })
+	total = max(total, len(prs))
+	if len(prs) > limit {
+		prs = prs[:limit]
+	}
+	return prs, total, nil
+}
+
+// PRLimit is the number of PRs ListMergedPRs keeps for a max_prs setting
+// of limit: DefaultMaxPRs for 0, and never more than search returns.
+func PRLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultMaxPRs
+	}
+	return min(limit, MaxSearchResults)
+}
+
+// TruncationNote is the report's warning for a PR list cut to the limit
+// most recently merged (see PRLimit); "" when total fits.
+func TruncationNote(repo string, total, limit int) string {
+	limit = PRLimit(limit)
+	if total <= limit {
+		return ""
+	}
+	return fmt.Sprintf("%s: 머지된 PR %d개 중 가장 최근에 머지된 %d개만 포함했습니다 (%d개 생략, max_prs)", repo, total, limit, total-limit)
+}
+
+// getPaged fetches a paginated REST list until more than limit items are
+// seen, returning at most limit; truncated reports that more were
+// available.
+func getPaged[T any](ctx context.Context, a *API, path string, limit int) (items []T, truncated bool, err error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	for page := 1; len(items) <= limit; page++ {
+		var batch []T
+		if err := a.getJSON(ctx, fmt.Sprintf("%s%sper_page=100&page=%d", path, sep, page), &batch); err != nil {
+			return nil, false, err
+		}
+		items = append(items, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	if len(items) > limit {
+		return items[:limit], true, nil
+	}
+	return items, false, nil
+}
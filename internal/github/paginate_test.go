@@ -0,0 +1,74 @@
+package github
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageMergedPRsKeepsMostRecentlyMerged(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	// 생성 순(최신 생성 먼저)으로 온 페이지: #1은 오래전에 열렸지만 가장 최근에 머지됐다
+	pages := [][]PR{
+		{{Number: 5, MergedAt: day(5)}, {Number: 4, MergedAt: day(4)}},
+		{{Number: 3, MergedAt: day(3)}, {Number: 2, MergedAt: day(2)}},
+		{{Number: 1, MergedAt: day(9)}},
+	}
+	var calls int
+	query := func(vars map[string]any, out *searchPage) error {
+		i := calls
+		calls++
+		out.Search.IssueCount = 5
+		out.Search.Nodes = pages[i]
+		out.Search.PageInfo.HasNextPage = i < len(pages)-1
+		out.Search.PageInfo.EndCursor = string(rune('a' + i))
+		return nil
+	}
+
+	prs, total, err := pageMergedPRs(context.Background(), "q", 3, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for _, pr := range prs {
+		got = append(got, pr.Number)
+	}
+	if want := []int{1, 5, 4}; !slices.Equal(got, want) {
+		t.Errorf("PRs = %v, want %v", got, want)
+	}
+	if total != 5 {
+		t.Errorf("total = %d, want 5", total)
+	}
+	if calls != len(pages) {
+		t.Errorf("read %d pages, want %d", calls, len(pages))
+	}
+}
+
+func TestTruncationNote(t *testing.T) {
+	tests := []struct {
+		total, limit int
+		want         string
+	}{
+		{10, 20, ""},
+		{20, 20, ""},
+		{25, 20, "25개 중 가장 최근에 머지된 20개만 포함했습니다 (5개 생략"},
+		{DefaultMaxPRs, 0, ""},
+		{DefaultMaxPRs + 1, 0, "(1개 생략"},
+		// 검색은 1000개까지만 돌려주므로 그보다 큰 max_prs는 1000으로 본다
+		{MaxSearchResults + 200, 5000, "1200개 중 가장 최근에 머지된 1000개만 포함했습니다 (200개 생략"},
+	}
+	for _, tt := range tests {
+		got := TruncationNote("o/r", tt.total, tt.limit)
+		if tt.want == "" {
+			if got != "" {
+				t.Errorf("TruncationNote(%d, %d) = %q, want none", tt.total, tt.limit, got)
+			}
+			continue
+		}
+		if !strings.HasPrefix(got, "o/r: ") || !strings.Contains(got, tt.want) {
+			t.Errorf("TruncationNote(%d, %d) = %q, want it to contain %q", tt.total, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate limit resources, as named in X-RateLimit-Resource and /rate_limit.
+const (
+	ResourceCore    = "core"
+	ResourceGraphQL = "graphql"
+)
+
+// Retry policy for rate-limited requests.
+const (
+	maxRateLimitRetries = 5
+	// maxRateLimitWait is the longest single pause; a limit that resets
+	// later than this fails the request instead.
+	maxRateLimitWait = 15 * time.Minute
+	// secondaryBackoff is the first pause after a secondary rate limit
+	// without Retry-After, doubled on each retry (GitHub asks for ≥1 min).
+	secondaryBackoff = time.Minute
+)
+
+// RateLimit is the quota of one rate limit resource.
+type RateLimit struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+func (r RateLimit) String() string {
+	return fmt.Sprintf("%s %d/%d, resets %s", r.Resource, r.Remaining, r.Limit, r.Reset.Local().Format("15:04"))
+}
+
+// RateLimitWait reports a pause forced by GitHub rate limiting.
+type RateLimitWait struct {
+	Resource string
+	Until    time.Time
+	Attempt  int  // retry number, starting at 1; 0 for a pre-emptive pause
+	Primary  bool // hourly quota exhausted, rather than a secondary limit
+}
+
+func (w RateLimitWait) String() string {
+	return fmt.Sprintf("Rate limited (%s), resuming in %s", w.Reason(), time.Until(w.Until).Round(time.Second))
+}
+
+// Reason names the limit that was hit.
+func (w RateLimitWait) Reason() string {
+	if w.Primary {
+		return w.Resource + " quota exhausted"
+	}
+	return "secondary limit"
+}
+
+// RateLimitError is returned when a rate limit outlasts the retry policy.
+type RateLimitError struct {
+	Resource string
+	Reset    time.Time // zero if unknown
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "github: rate limited; try again later"
+	}
+	return fmt.Sprintf("github: %s rate limit exceeded until %s", e.Resource, e.Reset.Local().Format("15:04"))
+}
+
+// limiter tracks the quotas seen in responses and paces requests. Its
+// methods are safe for concurrent use and on a nil receiver.
+type limiter struct {
+	mu     sync.Mutex
+	quotas map[string]RateLimit
+	notify func(RateLimitWait)
+}
+
+func newLimiter() *limiter { return &limiter{quotas: map[string]RateLimit{}} }
+
+// setNotify installs the pause callback; nil removes it.
+func (l *limiter) setNotify(f func(RateLimitWait)) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.notify = f
+	l.mu.Unlock()
+}
+
+// observe records the X-RateLimit-* headers of a response.
+func (l *limiter) observe(h http.Header) {
+	if l == nil {
+		return
+	}
+	q, ok := quotaFromHeader(h)
+	if !ok {
+		return
+	}
+	l.mu.Lock()
+	l.quotas[q.Resource] = q
+	l.mu.Unlock()
+}
+
+// quota returns the last seen quota of resource.
+func (l *limiter) quota(resource string) (RateLimit, bool) {
+	if l == nil {
+		return RateLimit{}, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	q, ok := l.quotas[resource]
+	return q, ok
+}
+
+// ready blocks while resource's quota is known to be exhausted, so
+// requests queue up instead of failing.
+func (l *limiter) ready(ctx context.Context, resource string) error {
+	q, ok := l.quota(resource)
+	if !ok || q.Remaining > 0 || !time.Now().Before(q.Reset) {
+		return nil
+	}
+	return l.pause(ctx, RateLimitWait{Resource: resource, Until: q.Reset.Add(time.Second), Primary: true})
+}
+
+// pause reports w and sleeps until w.Until, or fails if that is too far
+// or ctx is done first.
+func (l *limiter) pause(ctx context.Context, w RateLimitWait) error {
+	if time.Until(w.Until) > maxRateLimitWait {
+		reset := time.Time{}
+		if w.Primary {
+			reset = w.Until
+		}
+		return &RateLimitError{Resource: w.Resource, Reset: reset}
+	}
+	if l != nil {
+		l.mu.Lock()
+		notify := l.notify
+		l.mu.Unlock()
+		if notify != nil {
+			notify(w)
+		}
+	}
+	t := time.NewTimer(time.Until(w.Until))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoff returns how long to wait before retry attempt of a request that
+// hit a secondary rate limit without a Retry-After hint.
+func backoff(attempt int) time.Duration {
+	return secondaryBackoff << (attempt - 1)
+}
+
+func quotaFromHeader(h http.Header) (RateLimit, bool) {
+	limit, err1 := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, err2 := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, err3 := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return RateLimit{}, false
+	}
+	resource := h.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = ResourceCore
+	}
+	return RateLimit{Resource: resource, Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}, true
+}
+
+// rateLimitWait decides whether a response is a rate limit rejection and,
+// if so, until when to wait before retry attempt. GraphQL reports its
+// limit as a 200 with a RATE_LIMITED error.
+func rateLimitWait(resource string, resp *http.Response, body []byte, attempt int) (RateLimitWait, bool) {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && (resp.Header.Get("Retry-After") != "" ||
+			resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			strings.Contains(strings.ToLower(string(body)), "rate limit")),
+		resp.StatusCode == http.StatusOK && resource == ResourceGraphQL && strings.Contains(string(body), `"RATE_LIMITED"`):
+	default:
+		return RateLimitWait{}, false
+	}
+
+	w := RateLimitWait{Resource: resource, Attempt: attempt}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		w.Until = time.Now().Add(time.Duration(secs) * time.Second)
+	} else if q, ok := quotaFromHeader(resp.Header); ok && q.Remaining == 0 {
+		w.Until, w.Primary = q.Reset.Add(time.Second), true
+	} else {
+		w.Until = time.Now().Add(backoff(attempt))
+	}
+	return w, true
+}
+
+// rateLimitResponse is the body of GET /rate_limit.
+type rateLimitResponse struct {
+	Resources map[string]struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	} `json:"resources"`
+}
+
+// limits returns the core and GraphQL quotas, the ones this tool uses.
+func (r rateLimitResponse) limits() []RateLimit {
+	var out []RateLimit
+	for _, name := range []string{ResourceCore, ResourceGraphQL} {
+		if q, ok := r.Resources[name]; ok {
+			out = append(out, RateLimit{Resource: name, Limit: q.Limit, Remaining: q.Remaining, Reset: time.Unix(q.Reset, 0)})
+		}
+	}
+	return out
+}
+
+// CallEstimate is the number of API requests a collection run will make
+// per rate limit resource.
+type CallEstimate struct {
+	REST    int
+	GraphQL int
+}
+
+// EstimateCalls counts the requests Collect will make for prs: one each
+// for the diff, .gitattributes, comments and reviews of every PR it
+// collects whose data is not cached. Bot PRs that Limits.BotPRs leaves out
+// cost nothing, and the file lists a path or owner filter needs are not
+// counted; InScope fetched them while listing, before prs was narrowed.
+func (c *Collector) EstimateCalls(repo string, prs []PR) CallEstimate {
+	var e CallEstimate
+	prs, _ = c.splitBotPRs(prs)
+	for _, pr := range prs {
+		for _, kind := range []string{"diff", "gitattributes"} {
+			if !c.Cache.Has(repo, pr, kind) {
+				e.REST++
+			}
+		}
+		if !c.Limits.SkipComments {
+			if !c.Cache.Has(repo, pr, "comments") {
+				e.REST++
+			}
+			if !c.Cache.Has(repo, pr, "reviews") {
+				e.GraphQL++
+			}
+		}
+	}
+	return e
+}
+
+func (e CallEstimate) String() string {
+	return fmt.Sprintf("~%d REST, ~%d GraphQL API calls", e.REST, e.GraphQL)
+}
+
+// Add returns the sum of e and o.
+func (e CallEstimate) Add(o CallEstimate) CallEstimate {
+	return CallEstimate{REST: e.REST + o.REST, GraphQL: e.GraphQL + o.GraphQL}
+}
+
+// Preflight compares e with the remaining quotas and returns a note for
+// each resource the run would exhaust; quotas that cannot be read are
+// skipped.
+func Preflight(ctx context.Context, client Client, e CallEstimate) []string {
+	limits, err := client.RateLimits(ctx)
+	if err != nil {
+		return nil
+	}
+	need := map[string]int{ResourceCore: e.REST, ResourceGraphQL: e.GraphQL}
+	var notes []string
+	for _, q := range limits {
+		if n := need[q.Resource]; n > q.Remaining {
+			notes = append(notes, fmt.Sprintf("~%d %s API calls needed but %d of %d remain until %s; the run will pause when the limit is hit",
+				n, q.Resource, q.Remaining, q.Limit, q.Reset.Local().Format("15:04")))
+		}
+	}
+	return notes
+}
@@ -0,0 +1,28 @@
+package github
+
+import "testing"
+
+func TestEstimateCalls(t *testing.T) {
+	bot := PR{Number: 2}
+	bot.Author.Login = "renovate[bot]"
+	prs := []PR{{Number: 1, ChangedFiles: 250}, bot}
+	bots := BotFilter{Suffix: true}
+	tests := []struct {
+		name   string
+		limits Limits
+		want   CallEstimate
+	}{
+		{"keep by default", Limits{Bots: bots}, CallEstimate{REST: 6, GraphQL: 2}},
+		{"skip bots", Limits{Bots: bots, BotPRs: BotPRsSkip}, CallEstimate{REST: 3, GraphQL: 1}},
+		{"group bots", Limits{Bots: bots, BotPRs: BotPRsGroup}, CallEstimate{REST: 3, GraphQL: 1}},
+		// InScope가 목록 단계에서 가져온 파일 목록은 세지 않는다
+		{"scope", Limits{Bots: bots, BotPRs: BotPRsSkip, Scope: ParseScope("apps/api/")}, CallEstimate{REST: 3, GraphQL: 1}},
+		{"no comments", Limits{Bots: bots, SkipComments: true}, CallEstimate{REST: 4}},
+	}
+	for _, tt := range tests {
+		c := &Collector{Limits: tt.limits}
+		if got := c.EstimateCalls("o/r", prs); got != tt.want {
+			t.Errorf("%s: EstimateCalls = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
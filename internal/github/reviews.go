@@ -0,0 +1,214 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Review is a submitted review and its verdict.
+type Review struct {
+	Author string
+	State  string // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	Body   string
+}
+
+// ReviewThread is an inline review conversation anchored to a diff line.
+type ReviewThread struct {
+	Path     string
+	Line     int
+	DiffHunk string
+	Resolved bool
+	Comments []Comment // the opening comment followed by its replies
+}
+
+// Reviews holds a PR's review verdicts and inline threads.
+type Reviews struct {
+	Verdicts []Review
+	Threads  []ReviewThread
+}
+
+// Display limits for formatReviews.
+const (
+	maxThreads     = 20
+	maxReplies     = 5
+	hunkContext    = 3   // diff lines shown above each thread
+	maxCommentChar = 300 // per comment, after collapsing whitespace
+)
+
+const reviewsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviews(first: 50) {
+        nodes { state body author { login } }
+      }
+      reviewThreads(first: 50) {
+        nodes {
+          isResolved path line originalLine
+          comments(first: 20) {
+            nodes { body diffHunk author { login } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// reviewsResponse is the data of reviewsQuery, shared by both backends.
+type reviewsResponse struct {
+	Repository struct {
+		PullRequest struct {
+			Reviews struct {
+				Nodes []struct {
+					State  string `json:"state"`
+					Body   string `json:"body"`
+					Author struct {
+						Login string `json:"login"`
+					} `json:"author"`
+				} `json:"nodes"`
+			} `json:"reviews"`
+			ReviewThreads struct {
+				Nodes []struct {
+					IsResolved   bool   `json:"isResolved"`
+					Path         string `json:"path"`
+					Line         int    `json:"line"`
+					OriginalLine int    `json:"originalLine"`
+					Comments     struct {
+						Nodes []struct {
+							Body     string `json:"body"`
+							DiffHunk string `json:"diffHunk"`
+							Author   struct {
+								Login string `json:"login"`
+							} `json:"author"`
+						} `json:"nodes"`
+					} `json:"comments"`
+				} `json:"nodes"`
+			} `json:"reviewThreads"`
+		} `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+func reviewsVars(repo string, number int) (map[string]any, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo %q", repo)
+	}
+	return map[string]any{"owner": owner, "name": name, "number": number}, nil
+}
+
+func (r reviewsResponse) reviews() Reviews {
+	var out Reviews
+	pr := r.Repository.PullRequest
+	for _, n := range pr.Reviews.Nodes {
+		out.Verdicts = append(out.Verdicts, Review{Author: n.Author.Login, State: n.State, Body: n.Body})
+	}
+	for _, n := range pr.ReviewThreads.Nodes {
+		t := ReviewThread{Path: n.Path, Line: n.Line, Resolved: n.IsResolved}
+		if t.Line == 0 {
+			t.Line = n.OriginalLine // outdated thread
+		}
+		for i, c := range n.Comments.Nodes {
+			if i == 0 {
+				t.DiffHunk = c.DiffHunk
+			}
+			t.Comments = append(t.Comments, Comment{Author: c.Author.Login, Body: c.Body})
+		}
+		out.Threads = append(out.Threads, t)
+	}
+	return out
+}
+
+// formatReviews renders verdicts and inline threads compactly, skipping
+// bots. Either part is "" when there is nothing to show.
+func formatReviews(r Reviews, bots BotFilter) (verdicts, threads string) {
+	var lines []string
+	for _, v := range r.Verdicts {
+		label := verdictLabel(v.State)
+		if label == "" || bots.IsBot(v.Author) {
+			continue
+		}
+		body := excerpt(v.Body)
+		if v.State == "COMMENTED" && body == "" {
+			continue // 인라인 코멘트만 담은 리뷰
+		}
+		line := fmt.Sprintf("- **%s** %s", v.Author, label)
+		if body != "" {
+			line += ": " + body
+		}
+		lines = append(lines, line)
+	}
+	verdicts = strings.Join(lines, "\n")
+
+	var b strings.Builder
+	shown := 0
+	for _, t := range r.Threads {
+		if len(t.Comments) == 0 || bots.IsBot(t.Comments[0].Author) {
+			continue
+		}
+		if shown == maxThreads {
+			fmt.Fprintf(&b, "- ... more threads omitted\n")
+			break
+		}
+		shown++
+
+		status := "open"
+		if t.Resolved {
+			status = "resolved"
+		}
+		fmt.Fprintf(&b, "- `%s:%d` (%s)\n", t.Path, t.Line, status)
+		if hunk := lastLines(t.DiffHunk, hunkContext); hunk != "" {
+			fmt.Fprintf(&b, "  ```diff\n%s\n  ```\n", indent(hunk, "  "))
+		}
+		replies := 0
+		for i, c := range t.Comments {
+			if bots.IsBot(c.Author) {
+				continue
+			}
+			if i > 0 {
+				if replies == maxReplies {
+					b.WriteString("    - ...\n")
+					break
+				}
+				replies++
+				fmt.Fprintf(&b, "    - **%s**: %s\n", c.Author, excerpt(c.Body))
+				continue
+			}
+			fmt.Fprintf(&b, "  - **%s**: %s\n", c.Author, excerpt(c.Body))
+		}
+	}
+	threads = strings.TrimRight(b.String(), "\n")
+	return verdicts, threads
+}
+
+func verdictLabel(state string) string {
+	switch state {
+	case "APPROVED":
+		return "approved"
+	case "CHANGES_REQUESTED":
+		return "requested changes"
+	case "COMMENTED":
+		return "commented"
+	}
+	return "" // PENDING, DISMISSED
+}
+
+// excerpt collapses whitespace and caps s at maxCommentChar runes.
+func excerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxCommentChar {
+		return string(r[:maxCommentChar]) + "…"
+	}
+	return s
+}
+
+// lastLines returns at most n trailing lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Scope limits a summary to the files under certain paths, e.g. one app of
+// a monorepo. Globs use .gitattributes syntax; a trailing "/" means
+// everything below that directory.
+type Scope struct {
+	Include []string // empty means every path
+	Exclude []string
+}
+
+// ParseScope reads the input panel's form: a comma-separated glob list in
+// which "!" marks an exclusion, e.g. "apps/api/**, !apps/api/legacy/**".
+func ParseScope(s string) Scope {
+	var sc Scope
+	for _, g := range strings.Split(s, ",") {
+		g = strings.TrimSpace(g)
+		if ex, ok := strings.CutPrefix(g, "!"); ok {
+			if ex = strings.TrimSpace(ex); ex != "" {
+				sc.Exclude = append(sc.Exclude, ex)
+			}
+		} else if g != "" {
+			sc.Include = append(sc.Include, g)
+		}
+	}
+	return sc
+}
+
+// String formats s in the form ParseScope reads; "" for no scope.
+func (s Scope) String() string {
+	parts := append([]string(nil), s.Include...)
+	for _, g := range s.Exclude {
+		parts = append(parts, "!"+g)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s Scope) IsZero() bool { return len(s.Include) == 0 && len(s.Exclude) == 0 }
+
+// Match reports whether path p is in scope. Exclusions win.
+func (s Scope) Match(p string) bool {
+	for _, g := range s.Exclude {
+		if matchScopeGlob(g, p) {
+			return false
+		}
+	}
+	if len(s.Include) == 0 {
+		return true
+	}
+	for _, g := range s.Include {
+		if matchScopeGlob(g, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchScopeGlob(g, p string) bool {
+	if strings.HasSuffix(g, "/") {
+		g += "**"
+	}
+	return matchAttrPattern(g, p)
+}
+
+// keepFiles returns the files of a parsed diff whose new or old path
+// matches.
+func keepFiles(files []FileDiff, match func(string) bool) []FileDiff {
+	var in []FileDiff
+	for _, f := range files {
+		if match(f.Path) || f.OldPath != "" && match(f.OldPath) {
+			in = append(in, f)
+		}
+	}
+	return in
+}
+
+// pathFilter returns the predicate for files the summary covers: those in
+// Limits.Scope and, in owner filter mode, owned by Limits.Owner. It is nil
+// when every file is covered.
+func (c *Collector) pathFilter(ctx context.Context, repo string) (func(string) bool, error) {
+	scope := c.Limits.Scope
+	if c.Limits.Owner == "" || c.Limits.OwnerMode == OwnerModeGroup {
+		if scope.IsZero() {
+			return nil, nil
+		}
+		return scope.Match, nil
+	}
+
+	co, ok, err := c.codeOwners(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("reading CODEOWNERS: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s has no CODEOWNERS file", repo)
+	}
+	return func(p string) bool {
+		return scope.Match(p) && isOwner(c.Limits.Owner, co.Owners(p))
+	}, nil
+}
+
+// InScope returns the PRs that touch at least one covered file (see
+// pathFilter), keeping their order. Each PR's file list is fetched
+// (through the cache) with the collector's worker pool; a PR whose list
+// cannot be fetched is kept, so one failed call drops nothing. It returns
+// prs unchanged when every file is covered.
+func (c *Collector) InScope(ctx context.Context, repo string, prs []PR) ([]PR, error) {
+	match, err := c.pathFilter(ctx, repo)
+	if err != nil || match == nil || len(prs) == 0 {
+		return prs, err
+	}
+
+	workers := c.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	keep := make([]bool, len(prs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(prs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				files, err := c.files(ctx, repo, prs[i])
+				if err != nil {
+					keep[i] = true // 판단할 수 없으면 남겨 두고 수집 단계에 맡긴다
+					continue
+				}
+				for _, f := range files {
+					if match(f) {
+						keep[i] = true
+						break
+					}
+				}
+			}
+		}()
+	}
+dispatch:
+	for i := range prs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var in []PR
+	for i, pr := range prs {
+		if keep[i] {
+			in = append(in, pr)
+		}
+	}
+	return in, nil
+}
+
+// files fetches the PR's changed file paths through the cache.
+func (c *Collector) files(ctx context.Context, repo string, pr PR) ([]string, error) {
+	var files []string
+	if c.Cache.Load(repo, pr, "files", &files) {
+		return files, nil
+	}
+	files, err := c.Client.ListFiles(ctx, repo, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	c.Cache.Store(repo, pr, "files", files)
+	return files, nil
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		in               string
+		include, exclude []string
+	}{
+		{"", nil, nil},
+		{" , ,", nil, nil},
+		{"apps/api/**", []string{"apps/api/**"}, nil},
+		{"apps/api/**, !apps/api/legacy/**", []string{"apps/api/**"}, []string{"apps/api/legacy/**"}},
+		{"! docs/ ,libs/", []string{"libs/"}, []string{"docs/"}},
+		{"!, !*.md", nil, []string{"*.md"}},
+	}
+	for _, tt := range tests {
+		s := ParseScope(tt.in)
+		if !slices.Equal(s.Include, tt.include) || !slices.Equal(s.Exclude, tt.exclude) {
+			t.Errorf("ParseScope(%q) = %q, %q; want %q, %q", tt.in, s.Include, s.Exclude, tt.include, tt.exclude)
+		}
+		if again := ParseScope(s.String()); !slices.Equal(again.Include, s.Include) || !slices.Equal(again.Exclude, s.Exclude) {
+			t.Errorf("ParseScope(%q).String() = %q does not round-trip", tt.in, s.String())
+		}
+	}
+}
+
+func TestScopeMatch(t *testing.T) {
+	tests := []struct {
+		scope, path string
+		want        bool
+	}{
+		{"", "anything.go", true},
+		{"apps/api/", "apps/api/main.go", true},
+		{"apps/api/", "apps/web/main.go", false},
+		{"apps/api/**", "apps/api/x/y.go", true},
+		{"*.md", "docs/guide.md", true},
+		{"!*.md", "docs/guide.md", false},
+		{"!*.md", "main.go", true},
+		// 제외가 포함을 이긴다
+		{"apps/api/, !apps/api/legacy/", "apps/api/legacy/old.go", false},
+		{"apps/api/, !apps/api/legacy/", "apps/api/new.go", true},
+	}
+	for _, tt := range tests {
+		if got := ParseScope(tt.scope).Match(tt.path); got != tt.want {
+			t.Errorf("ParseScope(%q).Match(%q) = %v, want %v", tt.scope, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestInScope(t *testing.T) {
+	client := &fakeClient{
+		prFiles: map[int][]string{
+			1: {"apps/api/main.go"},
+			2: {"apps/web/index.ts"},
+			4: {"apps/api/legacy/old.go"},
+		},
+		filesErr: map[int]error{3: errors.New("boom")},
+	}
+	c := &Collector{Client: client, Limits: Limits{Scope: ParseScope("apps/api/, !apps/api/legacy/")}}
+	prs := []PR{{Number: 1}, {Number: 2}, {Number: 3}, {Number: 4}}
+
+	in, err := c.InScope(context.Background(), "o/r", prs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for _, pr := range in {
+		got = append(got, pr.Number)
+	}
+	// #3은 파일 목록을 못 가져왔으므로 남는다
+	if want := []int{1, 3}; !slices.Equal(got, want) {
+		t.Errorf("InScope = %v, want %v", got, want)
+	}
+}
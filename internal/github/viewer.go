@@ -0,0 +1,61 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Viewer is the account a Client acts as.
+type Viewer struct {
+	Login string
+	// Scopes are the token's OAuth scopes; nil when the token does not
+	// list them (fine-grained and GitHub App tokens).
+	Scopes []string
+}
+
+// RequiredScopes are the OAuth scopes pr-news needs: repo for private
+// repositories and read:org for the organizations in the repo picker.
+var RequiredScopes = []string{"repo", "read:org"}
+
+// impliedBy lists the broader scopes that grant each required one.
+var impliedBy = map[string][]string{
+	"read:org": {"write:org", "admin:org"},
+}
+
+// MissingScopes returns the RequiredScopes v's token lacks; nil when the
+// token does not list its scopes.
+func (v Viewer) MissingScopes() []string {
+	if v.Scopes == nil {
+		return nil
+	}
+	var missing []string
+	for _, s := range RequiredScopes {
+		if !slices.Contains(v.Scopes, s) && !slices.ContainsFunc(impliedBy[s], func(o string) bool { return slices.Contains(v.Scopes, o) }) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// parseViewer reads a GET /user response and its X-OAuth-Scopes header.
+func parseViewer(header http.Header, body []byte) (Viewer, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return Viewer{}, fmt.Errorf("parsing user: %w", err)
+	}
+	v := Viewer{Login: user.Login}
+	if values := header.Values("X-OAuth-Scopes"); len(values) > 0 {
+		v.Scopes = []string{}
+		for _, s := range strings.Split(strings.Join(values, ","), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				v.Scopes = append(v.Scopes, s)
+			}
+		}
+	}
+	return v, nil
+}
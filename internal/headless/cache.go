@@ -0,0 +1,51 @@
+package headless
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eddy/pr-news/internal/github"
+)
+
+// RunCache implements `pr-news cache prune|clear|path`.
+func RunCache(args []string) int {
+	fs := flag.NewFlagSet("cache", flag.ContinueOnError)
+	olderThan := fs.Duration("older-than", 30*24*time.Hour, "prune: remove entries not written within this duration (0 keeps all)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: pr-news cache prune [-older-than 720h] | clear | path")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+	sub := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return ExitUsage
+	}
+
+	c := &github.Cache{Dir: github.DefaultCacheDir()}
+	switch sub {
+	case "prune":
+		n, err := c.Prune(*olderThan)
+		if err != nil {
+			logf("pruning cache: %v", err)
+			return ExitUsage
+		}
+		logf("Removed %d cache entries from %s", n, c.Dir)
+	case "clear":
+		if err := c.Clear(); err != nil {
+			logf("clearing cache: %v", err)
+			return ExitUsage
+		}
+		logf("Cleared %s", c.Dir)
+	case "path":
+		fmt.Fprintln(os.Stdout, c.Dir)
+	default:
+		fs.Usage()
+		return ExitUsage
+	}
+	return ExitOK
+}
@@ -0,0 +1,26 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/eddy/pr-news/internal/doctor"
+)
+
+// RunDoctor implements `pr-news doctor`: it prints the startup checks and
+// exits with ExitUsage if any failed.
+func RunDoctor(opts doctor.Options) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report := doctor.Run(ctx, opts)
+	if ctx.Err() != nil {
+		return interrupted()
+	}
+	fmt.Fprint(os.Stdout, report)
+	if report.Failed() {
+		return ExitUsage
+	}
+	return ExitOK
+}
@@ -0,0 +1,334 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"github.com/eddy/pr-news/internal/config"
+	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/history"
+	"github.com/eddy/pr-news/internal/llm"
+	"github.com/eddy/pr-news/internal/proc"
+	"github.com/eddy/pr-news/internal/retry"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK     = 0
+	ExitUsage  = 1 // invalid flags, unwritable output or a failed doctor check
+	ExitNoPRs  = 2
+	ExitGitHub = 3
+	ExitLLM    = 4
+
+	ExitInterrupted = 130 // Ctrl+C; the shell convention for SIGINT
+)
+
+// Options controls a single non-interactive run.
+type Options struct {
+	Repos  []string // owner/name; more than one produces a digest
+	Days   int
+	Branch string
+
+	// SinceLast fetches PRs merged after each repo's last-run mark instead
+	// of the last Days days; the first run falls back to Days.
+	SinceLast bool
+	Out       string // output file; "" or "-" writes to stdout
+
+	Workers     int    // PR collection concurrency
+	MaxPRs      int    // most recently merged PRs fetched per repo; 0 uses the default
+	TokenBudget int    // prompt size above which map-reduce is used
+	Prompt      string // custom prompt template; "" uses the built-in one
+	Limits      github.Limits
+	Timeouts    config.Timeouts // per-stage limits; zero means none
+
+	GitHub     github.Client
+	Cache      *github.Cache // nil disables the PR data cache
+	Summarizer llm.Summarizer
+	LLMRetry   retry.Policy   // bounds and retries each LLM call
+	History    *history.Store // nil skips saving the report
+	Marks      *history.Marks // per-repo last-run marks
+}
+
+// repoPRs is the fetch result for one repository.
+type repoPRs struct {
+	repo  string
+	prs   []github.PR
+	total int // merged PRs in the window, before the MaxPRs cap
+	err   error
+}
+
+// Run executes the fetch → collect → summarize pipeline without the TUI.
+// Progress goes to stderr so stdout stays clean markdown. An interrupt
+// cancels in-flight requests and subprocesses.
+func Run(opts Options) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if len(opts.Repos) == 0 {
+		logf("--repo is required")
+		return ExitUsage
+	}
+	for _, repo := range opts.Repos {
+		if !strings.Contains(repo, "/") {
+			logf("invalid --repo %q (expected owner/name)", repo)
+			return ExitUsage
+		}
+	}
+	if opts.Days <= 0 {
+		opts.Days = 7
+	}
+
+	c := github.Collector{Client: opts.GitHub, Workers: opts.Workers, Cache: opts.Cache, Limits: opts.Limits}
+	opts.GitHub.OnRateLimit(func(w github.RateLimitWait) { logf("%s", w) })
+	ctx = retry.WithNotify(ctx, func(a retry.Attempt) { logf("%s", a) })
+	scope := opts.Limits.ScopeLabel()
+
+	// 레포별 PR 목록은 병렬로 조회
+	fetchCtx, cancel := retry.WithTimeout(ctx, opts.Timeouts.Fetch)
+	defer cancel()
+	results := make([]repoPRs, len(opts.Repos))
+	var wg sync.WaitGroup
+	for i, repo := range opts.Repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prs, total, err := fetch(fetchCtx, opts, repo)
+			if err == nil && scope != "" {
+				n := len(prs)
+				if prs, err = c.InScope(fetchCtx, repo, prs); err == nil {
+					logf("%s: %d of %d PRs touch %s", repo, len(prs), n, scope)
+				}
+			}
+			err = retry.StageError(fetchCtx, "fetching PRs", opts.Timeouts.Fetch, err)
+			results[i] = repoPRs{repo: repo, prs: prs, total: total, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var fetched []repoPRs
+	var all []github.PR
+	var warnings []string
+	if ctx.Err() != nil {
+		return interrupted()
+	}
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			if len(opts.Repos) == 1 {
+				logError(r.err)
+				return ExitGitHub
+			}
+			// 다이제스트는 나머지 레포로 계속한다
+			logf("warning: %s: skipped: %v", r.repo, r.err)
+			warnings = append(warnings, github.SkippedNote(r.repo, r.err))
+			failed++
+			continue
+		}
+		if w := github.TruncationNote(r.repo, r.total, opts.MaxPRs); w != "" {
+			logf("warning: %s: only the %d most recently merged of %d PRs were fetched (max_prs)", r.repo, opts.maxPRs(), r.total)
+			warnings = append(warnings, w)
+		}
+		if len(r.prs) == 0 {
+			if len(opts.Repos) > 1 {
+				logf("%s: no merged PRs", r.repo)
+			}
+			continue
+		}
+		fetched = append(fetched, r)
+		all = append(all, r.prs...)
+	}
+	if failed == len(results) {
+		logf("Could not list merged PRs of any repo")
+		logHint(results[0].err)
+		return ExitGitHub
+	}
+	if len(all) == 0 {
+		logf("No merged PRs found")
+		return ExitNoPRs
+	}
+
+	collectCtx, cancel := retry.WithTimeout(ctx, opts.Timeouts.Collect)
+	defer cancel()
+	var estimate github.CallEstimate
+	for _, r := range fetched {
+		estimate = estimate.Add(c.EstimateCalls(r.repo, r.prs))
+	}
+	logf("Estimated %s", estimate)
+	for _, note := range github.Preflight(collectCtx, opts.GitHub, estimate) {
+		logf("warning: %s", note)
+	}
+
+	data := make([]llm.RepoData, 0, len(fetched))
+	offset := 0
+	for _, r := range fetched {
+		chunks, failures, err := c.Collect(collectCtx, r.repo, r.prs, func(done int, pr github.PR) {
+			if len(fetched) > 1 {
+				logf("[%d/%d] %s#%d: %s", offset+done, len(all), r.repo, pr.Number, pr.Title)
+			} else {
+				logf("[%d/%d] PR #%d: %s", done, len(all), pr.Number, pr.Title)
+			}
+		})
+		if ctx.Err() != nil {
+			return interrupted()
+		}
+		if err != nil {
+			logError(retry.StageError(collectCtx, "collecting PR data", opts.Timeouts.Collect, err))
+			return ExitGitHub
+		}
+		offset += len(r.prs)
+		for _, f := range failures {
+			logf("warning: %v", f)
+		}
+		for _, line := range failures.Summary(r.repo) {
+			logf("warning: %s", line)
+		}
+		if note := failures.Note(r.repo); note != "" {
+			warnings = append(warnings, note)
+		}
+		if len(chunks) == 0 {
+			continue // 봇 PR만 있어 모두 제외됨
+		}
+		rd := llm.RepoData{Repo: r.repo, Chunks: chunks, DateRange: dateRange(r.prs)}
+		if opts.Limits.OwnerMode == github.OwnerModeGroup {
+			if rd.Appendix, err = c.OwnerIndex(collectCtx, r.repo, r.prs); err != nil {
+				logf("%s: owner index: %v", r.repo, err)
+			}
+		}
+		data = append(data, rd)
+	}
+	if len(data) == 0 {
+		logf("No merged PRs left after skipping bot PRs")
+		return ExitNoPRs
+	}
+	dates := dateRange(all)
+
+	logf("Summarizing %d PRs (%s) with %s...", len(all), dates, opts.Summarizer.Name())
+	p := llm.Pipeline{Summarizer: opts.Summarizer, TokenBudget: opts.TokenBudget, Prompt: opts.Prompt, Scope: scope, Warnings: warnings, Retry: opts.LLMRetry}
+	summarizeCtx, cancel := retry.WithTimeout(ctx, opts.Timeouts.Summarize)
+	defer cancel()
+	var (
+		res llm.Result
+		err error
+	)
+	if len(data) == 1 {
+		res, err = p.Run(summarizeCtx, data[0])
+	} else {
+		res, err = p.RunDigest(summarizeCtx, data, dates)
+	}
+	if ctx.Err() != nil {
+		return interrupted()
+	}
+	if err != nil {
+		logError(retry.StageError(summarizeCtx, "summarizing", opts.Timeouts.Summarize, err))
+		return ExitLLM
+	}
+	if res.Mode == llm.ModeMapReduce {
+		logf("Used map-reduce over %d batches (~%d tokens)", res.Batches, res.Tokens)
+	}
+
+	if err := write(opts.Out, res.Summary+"\n"); err != nil {
+		logf("writing output: %v", err)
+		return ExitUsage
+	}
+
+	if opts.History != nil {
+		numbers := make([]int, len(all))
+		for i, pr := range all {
+			numbers[i] = pr.Number
+		}
+		err := opts.History.Save(&history.Report{
+			Repo:      strings.Join(opts.Repos, ", "),
+			Branch:    opts.Branch,
+			DateRange: dates,
+			PRNumbers: numbers,
+			Model:     opts.Summarizer.Name(),
+			Markdown:  res.Summary,
+		})
+		if err != nil {
+			logf("warning: %v", err)
+		}
+	}
+	if opts.Marks != nil {
+		for _, r := range fetched {
+			if err := opts.Marks.Advance(r.repo, history.MarkFor(r.prs)); err != nil {
+				logf("warning: %v", err)
+			}
+		}
+	}
+	return ExitOK
+}
+
+func (o Options) maxPRs() int { return github.PRLimit(o.MaxPRs) }
+
+// fetch lists the most recently merged PRs of repo in the requested window; total counts
+// every PR in the window.
+func fetch(ctx context.Context, opts Options, repo string) (prs []github.PR, total int, err error) {
+	since := github.DaysAgo(opts.Days)
+	var mark *history.Mark
+	if opts.SinceLast && opts.Marks != nil {
+		if mk, ok := opts.Marks.Get(repo); ok {
+			since, mark = mk.MergedAt, &mk
+		} else {
+			logf("No previous run recorded for %s; using the last %d days", repo, opts.Days)
+		}
+	}
+
+	if mark != nil {
+		logf("Fetching PRs merged in %s since last run (%s)...", repo, since.Local().Format("2006-01-02 15:04"))
+	} else {
+		logf("Fetching merged PRs from %s (last %d days)...", repo, opts.Days)
+	}
+	prs, total, err = opts.GitHub.ListMergedPRs(ctx, repo, since, opts.Branch, opts.maxPRs())
+	if err != nil {
+		return nil, 0, err
+	}
+	if mark != nil {
+		prs = mark.Unseen(prs)
+	}
+	return prs, total, nil
+}
+
+func interrupted() int {
+	logf("Interrupted")
+	return ExitInterrupted
+}
+
+func dateRange(prs []github.PR) string {
+	start, end := github.DateRange(prs)
+	return fmt.Sprintf("%s ~ %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+}
+
+func write(path, content string) error {
+	var w io.Writer = os.Stdout
+	if path != "" && path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	_, err := io.WriteString(w, content)
+	return err
+}
+
+func logf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// logError logs a fatal err followed by its remediation hint, if any.
+func logError(err error) {
+	logf("%v", err)
+	logHint(err)
+}
+
+func logHint(err error) {
+	if hint := proc.Hint(err); hint != "" {
+		logf("hint: %s", hint)
+	}
+}
@@ -0,0 +1,92 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines kept around each change.
+const diffContext = 3
+
+// Diff returns a unified-style line diff from a to b. Unchanged stretches
+// longer than the context window are collapsed into "@@" separators.
+func Diff(a, b string) string {
+	x := strings.Split(a, "\n")
+	y := strings.Split(b, "\n")
+
+	// LCS 테이블 (보고서는 수백 줄 이하라 O(n*m)으로 충분)
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte // ' ', '-', '+'
+		text string
+		pos  int // 1-based line number in b
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, line{' ', x[i], j + 1})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, line{'-', x[i], j + 1})
+			i++
+		default:
+			lines = append(lines, line{'+', y[j], j + 1})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		lines = append(lines, line{'-', x[i], j + 1})
+	}
+	for ; j < len(y); j++ {
+		lines = append(lines, line{'+', y[j], j + 1})
+	}
+
+	// 변경 주변만 남기기
+	keep := make([]bool, len(lines))
+	changed := false
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		changed = true
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+	if !changed {
+		return "(no differences)"
+	}
+
+	var out strings.Builder
+	skipped := false
+	for k, l := range lines {
+		if !keep[k] {
+			skipped = true
+			continue
+		}
+		if skipped || k == 0 {
+			fmt.Fprintf(&out, "@@ line %d @@\n", l.pos)
+			skipped = false
+		}
+		out.WriteByte(l.op)
+		out.WriteString(l.text)
+		out.WriteByte('\n')
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
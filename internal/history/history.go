@@ -0,0 +1,113 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report is a generated summary saved for later browsing.
+type Report struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Repo      string    `json:"repo"`
+	Branch    string    `json:"branch,omitempty"`
+	DateRange string    `json:"dateRange"`
+	PRNumbers []int     `json:"prNumbers"`
+	Model     string    `json:"model"`
+	Markdown  string    `json:"markdown"`
+}
+
+// Title is a one-line description used in lists.
+func (r Report) Title() string {
+	branch := r.Branch
+	if branch == "" {
+		branch = "all"
+	}
+	return fmt.Sprintf("%s  %s (%s)  %d PRs", r.CreatedAt.Local().Format("2006-01-02 15:04"), r.Repo, branch, len(r.PRNumbers))
+}
+
+// Store keeps one JSON file per report in Dir.
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns $XDG_DATA_HOME/pr-news/history (~/.local/share by default).
+func DefaultDir() string {
+	return filepath.Join(dataDir(), "history")
+}
+
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "pr-news")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "share", "pr-news")
+	}
+	return filepath.Join(os.TempDir(), "pr-news")
+}
+
+// Save writes r, assigning ID and CreatedAt when unset.
+func (s *Store) Save(r *Report) error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	if r.ID == "" {
+		// 정렬 가능한 ID: 생성 시각 + 레포
+		r.ID = r.CreatedAt.UTC().Format("20060102T150405.000") + "-" + strings.ReplaceAll(r.Repo, "/", "_")
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("saving report: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("saving report: %w", err)
+	}
+	if err := os.WriteFile(s.path(r.ID), data, 0o644); err != nil {
+		return fmt.Errorf("saving report: %w", err)
+	}
+	return nil
+}
+
+// List returns all saved reports, newest first.
+func (s *Store) List() ([]Report, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing history: %w", err)
+	}
+	var reports []Report
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.Dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var r Report
+		if json.Unmarshal(data, &r) == nil {
+			reports = append(reports, r)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].CreatedAt.After(reports[j].CreatedAt) })
+	return reports, nil
+}
+
+// Delete removes the report with the given ID.
+func (s *Store) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		return fmt.Errorf("deleting report: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.Dir, filepath.Base(id)+".json")
+}
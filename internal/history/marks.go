@@ -0,0 +1,103 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eddy/pr-news/internal/github"
+)
+
+// Mark is a per-repo high-water mark: the newest PR included in the last
+// successful summary.
+type Mark struct {
+	MergedAt time.Time `json:"mergedAt"`
+	Number   int       `json:"number"`
+	RunAt    time.Time `json:"runAt"`
+}
+
+// MarkFor returns the mark for the newest merged PR in prs.
+func MarkFor(prs []github.PR) Mark {
+	var mk Mark
+	for _, pr := range prs {
+		if pr.MergedAt.After(mk.MergedAt) {
+			mk.MergedAt = pr.MergedAt
+			mk.Number = pr.Number
+		}
+	}
+	mk.RunAt = time.Now()
+	return mk
+}
+
+// Unseen drops PRs at or before the mark.
+func (mk Mark) Unseen(prs []github.PR) []github.PR {
+	var out []github.PR
+	for _, pr := range prs {
+		if pr.Number == mk.Number || !pr.MergedAt.After(mk.MergedAt) {
+			continue
+		}
+		out = append(out, pr)
+	}
+	return out
+}
+
+// Marks persists one Mark per repository in a single JSON file.
+type Marks struct {
+	Path string
+}
+
+// DefaultMarksPath returns $XDG_DATA_HOME/pr-news/marks.json.
+func DefaultMarksPath() string {
+	return filepath.Join(dataDir(), "marks.json")
+}
+
+func (m *Marks) load() (map[string]Mark, error) {
+	marks := make(map[string]Mark)
+	data, err := os.ReadFile(m.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return marks, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, err
+	}
+	return marks, nil
+}
+
+// Get returns the mark for repo; ok is false on the first run.
+func (m *Marks) Get(repo string) (Mark, bool) {
+	marks, err := m.load()
+	if err != nil {
+		return Mark{}, false
+	}
+	mk, ok := marks[repo]
+	return mk, ok
+}
+
+// Advance records mk for repo unless an existing mark is already newer.
+func (m *Marks) Advance(repo string, mk Mark) error {
+	marks, err := m.load()
+	if err != nil {
+		return fmt.Errorf("reading marks: %w", err)
+	}
+	if cur, ok := marks[repo]; ok && cur.MergedAt.After(mk.MergedAt) {
+		return nil
+	}
+	marks[repo] = mk
+
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.Path), 0o755); err != nil {
+		return fmt.Errorf("saving marks: %w", err)
+	}
+	if err := os.WriteFile(m.Path, data, 0o644); err != nil {
+		return fmt.Errorf("saving marks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	anthropicDefaultURL   = "https://api.anthropic.com"
+	anthropicDefaultModel = "claude-sonnet-4-5"
+	anthropicVersion      = "2023-06-01"
+	anthropicMaxTokens    = 8192
+)
+
+// Anthropic calls the Messages API directly.
+type Anthropic struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+}
+
+func (a *Anthropic) model() string {
+	if a.Model != "" {
+		return a.Model
+	}
+	return anthropicDefaultModel
+}
+
+func (a *Anthropic) Name() string { return ProviderAnthropic + "/" + a.model() }
+
+func (a *Anthropic) baseURL() string {
+	base := a.BaseURL
+	if base == "" {
+		base = anthropicDefaultURL
+	}
+	return strings.TrimRight(base, "/")
+}
+
+func (a *Anthropic) endpoint() string { return a.baseURL() + "/v1/messages" }
+
+// Check lists the available models, which needs a valid key.
+func (a *Anthropic) Check(ctx context.Context) (string, error) {
+	var resp struct{}
+	if err := getJSON(ctx, a.baseURL()+"/v1/models", a.headers(), &resp); err != nil {
+		return "", err
+	}
+	return a.baseURL(), nil
+}
+
+func (a *Anthropic) headers() map[string]string {
+	return map[string]string{
+		"x-api-key":         a.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+}
+
+func (a *Anthropic) Complete(ctx context.Context, system, prompt string) (string, error) {
+	req := map[string]any{
+		"model":      a.model(),
+		"max_tokens": anthropicMaxTokens,
+		"system":     system,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := postJSON(ctx, a.endpoint(), a.headers(), req, &resp); err != nil {
+		return "", fmt.Errorf("anthropic summarize: %w", err)
+	}
+
+	var b strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String(), nil
+}
+
+// Stream uses the Messages API's server-sent events.
+func (a *Anthropic) Stream(ctx context.Context, system, prompt string, onChunk func(string)) (string, error) {
+	req := map[string]any{
+		"model":      a.model(),
+		"max_tokens": anthropicMaxTokens,
+		"system":     system,
+		"stream":     true,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	var b strings.Builder
+	err := postStream(ctx, a.endpoint(), a.headers(), req, func(line string) error {
+		data, ok := sseData(line)
+		if !ok {
+			return nil
+		}
+		var ev struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Text string `json:"text"`
+			} `json:"delta"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return nil
+		}
+		switch ev.Type {
+		case "content_block_delta":
+			if ev.Delta.Text != "" {
+				b.WriteString(ev.Delta.Text)
+				onChunk(ev.Delta.Text)
+			}
+		case "error":
+			return fmt.Errorf("%s", ev.Error.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("anthropic summarize: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eddy/pr-news/internal/proc"
+)
+
+// ClaudeCLI runs the local `claude` binary in print mode.
+type ClaudeCLI struct {
+	Model string // optional; passed as --model
+}
+
+func (c *ClaudeCLI) Name() string {
+	if c.Model != "" {
+		return ProviderClaudeCLI + "/" + c.Model
+	}
+	return ProviderClaudeCLI
+}
+
+func (c *ClaudeCLI) command(system, prompt string) proc.Cmd {
+	args := []string{"-p", "--system-prompt", system}
+	if c.Model != "" {
+		args = append(args, "--model", c.Model)
+	}
+	return proc.Cmd{Name: "claude", Args: args, Stdin: strings.NewReader(prompt), Classify: classifyClaude}
+}
+
+// classifyClaude recognizes the claude CLI's messages for the failures a
+// user can fix.
+func classifyClaude(e *proc.Error) {
+	s := strings.ToLower(e.Stderr)
+	switch {
+	case e.Kind == proc.ErrNotInstalled:
+		e.Hint = "Install the claude CLI (npm install -g @anthropic-ai/claude-code), or choose another provider with --provider."
+	case strings.Contains(s, "/login") || strings.Contains(s, "not logged in") ||
+		strings.Contains(s, "invalid api key") || strings.Contains(s, "oauth token"):
+		e.Kind = proc.ErrNotAuthenticated
+		e.Hint = "Run `claude` and sign in with /login, or set ANTHROPIC_API_KEY and use --provider anthropic."
+	case strings.Contains(s, "usage limit") || strings.Contains(s, "rate limit"):
+		e.Kind = proc.ErrRateLimited
+		e.Hint = "Wait for the Claude usage limit to reset, or choose another provider with --provider."
+	}
+}
+
+// Check runs `claude --version`. Whether the CLI is logged in only shows
+// on the first summary.
+func (c *ClaudeCLI) Check(ctx context.Context) (string, error) {
+	out, err := proc.Cmd{Name: "claude", Args: []string{"--version"}, Classify: classifyClaude}.Output(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *ClaudeCLI) Complete(ctx context.Context, system, prompt string) (string, error) {
+	out, err := c.command(system, prompt).Output(ctx)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", fmt.Errorf("claude summarize: %w", err)
+	}
+	return string(out), nil
+}
+
+// streamArgs make the CLI print one JSON event per line, including each
+// text delta as it is generated.
+var streamArgs = []string{"--output-format", "stream-json", "--verbose", "--include-partial-messages"}
+
+// Stream runs the CLI with stream-json output and passes each text delta
+// on as it arrives.
+func (c *ClaudeCLI) Stream(ctx context.Context, system, prompt string, onChunk func(string)) (string, error) {
+	cmd := c.command(system, prompt)
+	cmd.Args = append(cmd.Args, streamArgs...)
+	w := &eventWriter{onChunk: onChunk}
+	err := cmd.Stream(ctx, w)
+	w.flush()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if w.failed {
+		// 오류도 result 이벤트로 오므로 JSON 대신 그 메시지를 보여 준다
+		e := &proc.Error{Name: cmd.Name, Args: cmd.Args, Err: errors.New("claude reported an error"), Stderr: w.result}
+		var pe *proc.Error
+		if errors.As(err, &pe) {
+			e.Err = pe.Err
+		}
+		classifyClaude(e)
+		err = e
+	}
+	if err != nil {
+		return "", fmt.Errorf("claude summarize: %w", err)
+	}
+	if w.result != "" {
+		return w.result, nil
+	}
+	return w.text.String(), nil
+}
+
+// streamEvent is the part of a stream-json line Stream reads.
+type streamEvent struct {
+	Type    string `json:"type"`
+	IsError bool   `json:"is_error"`
+	Result  string `json:"result"`
+	Event   struct {
+		Type  string `json:"type"`
+		Delta struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"delta"`
+	} `json:"event"`
+}
+
+// eventWriter splits stream-json output into lines, hands text deltas to
+// onChunk and keeps the final result event.
+type eventWriter struct {
+	line    []byte // 아직 줄바꿈이 오지 않은 부분
+	text    strings.Builder
+	result  string
+	failed  bool
+	onChunk func(string)
+}
+
+func (w *eventWriter) Write(p []byte) (int, error) {
+	w.line = append(w.line, p...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.event(w.line[:i])
+		w.line = w.line[i+1:]
+	}
+}
+
+// flush handles a last line without a line break.
+func (w *eventWriter) flush() {
+	if len(w.line) > 0 {
+		w.event(w.line)
+		w.line = nil
+	}
+}
+
+func (w *eventWriter) event(line []byte) {
+	var ev streamEvent
+	if json.Unmarshal(line, &ev) != nil {
+		return // JSON이 아닌 줄은 건너뛴다
+	}
+	switch {
+	case ev.Type == "stream_event" && ev.Event.Type == "content_block_delta" && ev.Event.Delta.Type == "text_delta":
+		w.text.WriteString(ev.Event.Delta.Text)
+		w.onChunk(ev.Event.Delta.Text)
+	case ev.Type == "result":
+		w.result, w.failed = ev.Result, ev.IsError
+	}
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eddy/pr-news/internal/proc"
+)
+
+// fakeClaude puts a claude script that prints out and exits with code on
+// PATH. It returns the script's directory, where it saves its arguments.
+func fakeClaude(t *testing.T, out string, code int) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "out"), []byte(out), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s/args\ncat > /dev/null\ncat %s/out\nexit %d\n", dir, dir, code)
+	if err := os.WriteFile(filepath.Join(dir, "claude"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+const streamOut = `{"type":"system","subtype":"init"}
+{"type":"stream_event","event":{"type":"message_start"}}
+{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"## 요"}}}
+{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"약\n"}}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"## 요약\n"}]}}
+{"type":"result","subtype":"success","is_error":false,"result":"## 요약\n"}
+`
+
+func TestClaudeCLIStream(t *testing.T) {
+	dir := fakeClaude(t, streamOut, 0)
+	var chunks []string
+	out, err := (&ClaudeCLI{}).Stream(context.Background(), "sys", "prompt", func(s string) { chunks = append(chunks, s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "## 요약\n" {
+		t.Errorf("Stream = %q", out)
+	}
+	if strings.Join(chunks, "|") != "## 요|약\n" {
+		t.Errorf("chunks = %q, want the two text deltas", chunks)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "args"))
+	for _, a := range streamArgs {
+		if !strings.Contains(string(args), a) {
+			t.Errorf("args %q lack %s", args, a)
+		}
+	}
+}
+
+func TestClaudeCLIStreamError(t *testing.T) {
+	fakeClaude(t, `{"type":"result","subtype":"success","is_error":true,"result":"Invalid API key · Please run /login"}`+"\n", 1)
+	_, err := (&ClaudeCLI{}).Stream(context.Background(), "sys", "prompt", func(string) {})
+	if !errors.Is(err, proc.ErrNotAuthenticated) {
+		t.Fatalf("err = %v, want ErrNotAuthenticated", err)
+	}
+	if strings.Contains(err.Error(), `"type"`) || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Errorf("err = %q, want the result message rather than JSON", err)
+	}
+}
+
+func TestEventWriterSplitLines(t *testing.T) {
+	var chunks []string
+	w := &eventWriter{onChunk: func(s string) { chunks = append(chunks, s) }}
+	for i := 0; i < len(streamOut); i += 7 { // 줄 중간에서 잘린 쓰기
+		w.Write([]byte(streamOut[i:min(i+7, len(streamOut))]))
+	}
+	w.flush()
+	if strings.Join(chunks, "") != "## 요약\n" || w.result != "## 요약\n" || w.failed {
+		t.Errorf("chunks %q, result %q, failed %v", chunks, w.result, w.failed)
+	}
+}
@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// httpClient is shared by the HTTP providers. It has no timeout of its own;
+// each call is bounded by the Pipeline's retry policy (llm.call_timeout).
+var httpClient = &http.Client{}
+
+// StatusError is a non-2xx response from an LLM provider.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// postJSON sends body as JSON to url and decodes the JSON response into out.
+func postJSON(ctx context.Context, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return doJSON(ctx, http.MethodPost, url, headers, payload, out)
+}
+
+// getJSON fetches url and decodes the JSON response into out.
+func getJSON(ctx context.Context, url string, headers map[string]string, out any) error {
+	return doJSON(ctx, http.MethodGet, url, headers, nil, out)
+}
+
+func doJSON(ctx context.Context, method, url string, headers map[string]string, payload []byte, out any) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: snippet(data)}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// snippet trims an error response body to something printable.
+func snippet(b []byte) string {
+	s := strings.TrimSpace(string(b))
+	if len(s) > 300 {
+		s = s[:300] + "..."
+	}
+	return s
+}
+
+// postStream sends body as JSON to url and calls onLine for every line of the
+// response body as it arrives (SSE or NDJSON). Returning an error from onLine
+// stops reading.
+func postStream(ctx context.Context, url string, headers map[string]string, body any, onLine func(line string) error) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		data, _ := io.ReadAll(resp.Body)
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: snippet(data)}
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		if err := onLine(sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// sseData returns the payload of an SSE "data:" line.
+func sseData(line string) (string, bool) {
+	data, ok := strings.CutPrefix(line, "data:")
+	return strings.TrimSpace(data), ok
+}
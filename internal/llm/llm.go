@@ -1,9 +1,16 @@
 package llm
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"net/http"
+	"os"
 	"strings"
+	"time"
+
+	"github.com/eddy/pr-news/internal/proc"
+	"github.com/eddy/pr-news/internal/retry"
 )
 
 const systemPrompt = `당신은 GitHub PR 변경사항을 분석하여 팀원이 따라잡아야 할 핵심 내용을 요약하는 역할입니다.
@@ -21,10 +28,154 @@ const systemPrompt = `당신은 GitHub PR 변경사항을 분석하여 팀원이
 - bullet points 사용
 - 핵심만 추출 (장황하게 X)`
 
-// Summarize sends PR data to Claude CLI and returns the summary.
-func Summarize(prData, repo string, prCount int, dateRange string) (string, error) {
-	userPrompt := fmt.Sprintf(`다음은 %s 레포지토리의 최근 머지된 PR %d개입니다.
-기간: %s
+// Summarizer is an LLM backend that completes a single prompt.
+type Summarizer interface {
+	// Name identifies the provider and model, e.g. "ollama/llama3.1".
+	Name() string
+	Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+}
+
+// Streamer is implemented by summarizers that can deliver output as it is
+// generated. onChunk receives each new piece of text; the full text is
+// returned at the end.
+type Streamer interface {
+	Stream(ctx context.Context, systemPrompt, userPrompt string, onChunk func(string)) (string, error)
+}
+
+// ErrModelMissing reports that a provider does not have the configured
+// model.
+var ErrModelMissing = errors.New("model not available")
+
+// Checker is implemented by summarizers that can verify, without spending
+// tokens, that the provider is reachable and accepts the credentials.
+// The returned detail describes what was found, e.g. a CLI version.
+type Checker interface {
+	Check(ctx context.Context) (detail string, err error)
+}
+
+func isStreamer(s Summarizer) bool {
+	_, ok := s.(Streamer)
+	return ok
+}
+
+// complete sends one prompt to p.Summarizer, using Stream when onChunk is
+// set and the Summarizer supports it. Transient failures are retried per
+// p.Retry; a stream is retried only while none of it has been emitted, so
+// output is never repeated.
+func (p *Pipeline) complete(ctx context.Context, system, prompt string, onChunk func(string)) (string, error) {
+	var out string
+	streamed := false
+	err := retry.Do(ctx, p.Retry, p.Summarizer.Name(), func(err error) bool {
+		return !streamed && transient(err)
+	}, func(ctx context.Context) error {
+		var err error
+		if st, ok := p.Summarizer.(Streamer); ok && onChunk != nil {
+			out, err = st.Stream(ctx, system, prompt, func(s string) {
+				streamed = true
+				onChunk(s)
+			})
+		} else {
+			out, err = p.Summarizer.Complete(ctx, system, prompt)
+		}
+		return err
+	})
+	return out, err
+}
+
+// transient reports whether a provider error may go away on retry: network
+// failures, rate limits (429), server errors including 529 Overloaded, and
+// the same reported in a stream or by the claude CLI.
+func transient(err error) bool {
+	if errors.Is(err, proc.ErrNotInstalled) || errors.Is(err, proc.ErrNotAuthenticated) {
+		return false // 다시 시도해도 같은 결과
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
+	if retry.Transient(err) {
+		return true
+	}
+	s := strings.ToLower(err.Error())
+	return strings.Contains(s, "overloaded") || strings.Contains(s, "rate limit") ||
+		strings.Contains(s, "api error: 5")
+}
+
+// Provider names accepted by New.
+const (
+	ProviderClaudeCLI = "claude-cli"
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+	ProviderOllama    = "ollama"
+)
+
+// Config selects and configures a Summarizer.
+type Config struct {
+	Provider string
+	Model    string
+	BaseURL  string
+	APIKey   string
+
+	CallTimeout time.Duration // per LLM call; 0 means none
+	Retries     int           // extra attempts after a transient failure
+}
+
+// RetryPolicy returns the policy for Pipeline.Retry.
+func (c Config) RetryPolicy() retry.Policy {
+	return retry.Policy{Retries: c.Retries, Timeout: c.CallTimeout}
+}
+
+// withEnv fills an empty API key and base URL from PR_NEWS_LLM_API_KEY and
+// each provider's conventional variables.
+func (c Config) withEnv() Config {
+	if c.APIKey == "" {
+		c.APIKey = os.Getenv("PR_NEWS_LLM_API_KEY")
+	}
+	if c.APIKey == "" {
+		switch c.Provider {
+		case ProviderAnthropic:
+			c.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+		case ProviderOpenAI:
+			c.APIKey = os.Getenv("OPENAI_API_KEY")
+		}
+	}
+	if c.BaseURL == "" {
+		switch c.Provider {
+		case ProviderOpenAI:
+			c.BaseURL = os.Getenv("OPENAI_BASE_URL")
+		case ProviderOllama:
+			c.BaseURL = os.Getenv("OLLAMA_HOST")
+		}
+	}
+	return c
+}
+
+// New returns the Summarizer for cfg.Provider.
+func New(cfg Config) (Summarizer, error) {
+	cfg = cfg.withEnv()
+	switch cfg.Provider {
+	case "", ProviderClaudeCLI:
+		return &ClaudeCLI{Model: cfg.Model}, nil
+	case ProviderAnthropic:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("anthropic provider requires ANTHROPIC_API_KEY")
+		}
+		return &Anthropic{APIKey: cfg.APIKey, Model: cfg.Model, BaseURL: cfg.BaseURL}, nil
+	case ProviderOpenAI:
+		if cfg.APIKey == "" && cfg.BaseURL == "" {
+			return nil, fmt.Errorf("openai provider requires OPENAI_API_KEY or a base URL")
+		}
+		return &OpenAI{APIKey: cfg.APIKey, Model: cfg.Model, BaseURL: cfg.BaseURL}, nil
+	case ProviderOllama:
+		return &Ollama{Model: cfg.Model, BaseURL: cfg.BaseURL}, nil
+	}
+	return nil, fmt.Errorf("unknown LLM provider %q (want %s, %s, %s or %s)",
+		cfg.Provider, ProviderClaudeCLI, ProviderAnthropic, ProviderOpenAI, ProviderOllama)
+}
+
+func summaryPrompt(prData, repo string, prCount int, dateRange, scope string) string {
+	return fmt.Sprintf(`다음은 %s 레포지토리의 최근 머지된 PR %d개입니다.
+기간: %s%s
 컨트리뷰터로서 따라잡아야 할 핵심 내용을 요약해주세요.
 
 ---
@@ -33,7 +184,16 @@ func Summarize(prData, repo string, prCount int, dateRange string) (string, erro
 
 위 PR들을 분석하여 다음 섹션으로 요약해주세요:
 
-# %s PR 요약 (%s)
+%s`, repo, prCount, dateRange, scopeNote(scope), prData, reportFormat(repo, dateRange, scope))
+}
+
+// reportFormat is the four-section layout every final report follows.
+func reportFormat(repo, dateRange, scope string) string {
+	title := fmt.Sprintf("# %s PR 요약 (%s)", repo, dateRange)
+	if scope != "" {
+		title += "\n> 범위: " + scope
+	}
+	return title + `
 
 ## 📦 주요 변경사항
 (새 기능, 개선, 리팩토링 등)
@@ -45,14 +205,13 @@ func Summarize(prData, repo string, prCount int, dateRange string) (string, erro
 (리뷰 코멘트에서 얻은 인사이트, 코드 패턴 등)
 
 ## ⚠️ 주의사항
-(breaking changes, 마이그레이션 필요 등 - 있는 경우만)`, repo, prCount, dateRange, prData, repo, dateRange)
-
-	cmd := exec.Command("claude", "-p", "--system-prompt", systemPrompt)
-	cmd.Stdin = strings.NewReader(userPrompt)
+(breaking changes, 마이그레이션 필요 등 - 있는 경우만)`
+}
 
-	out, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("claude summarize: %w", err)
+// scopeNote tells the model that PRs and diffs were limited to scope.
+func scopeNote(scope string) string {
+	if scope == "" {
+		return ""
 	}
-	return strings.TrimSpace(string(out)), nil
+	return "\n범위: " + scope + " (이 범위의 파일을 건드린 PR과 해당 파일의 diff만 포함)"
 }
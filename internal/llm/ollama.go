@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	ollamaDefaultURL   = "http://localhost:11434"
+	ollamaDefaultModel = "llama3.1"
+)
+
+// Ollama calls a local Ollama server's chat API.
+type Ollama struct {
+	Model   string
+	BaseURL string
+}
+
+func (o *Ollama) model() string {
+	if o.Model != "" {
+		return o.Model
+	}
+	return ollamaDefaultModel
+}
+
+func (o *Ollama) Name() string { return ProviderOllama + "/" + o.model() }
+
+func (o *Ollama) baseURL() string {
+	base := o.BaseURL
+	if base == "" {
+		base = ollamaDefaultURL
+	}
+	if !strings.Contains(base, "://") {
+		base = "http://" + base // OLLAMA_HOST is often host:port
+	}
+	return strings.TrimRight(base, "/")
+}
+
+func (o *Ollama) endpoint() string { return o.baseURL() + "/api/chat" }
+
+// Check lists the local models and reports one that has not been pulled.
+func (o *Ollama) Check(ctx context.Context) (string, error) {
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := getJSON(ctx, o.baseURL()+"/api/tags", nil, &resp); err != nil {
+		return "", err
+	}
+	for _, m := range resp.Models {
+		if m.Name == o.model() || m.Name == o.model()+":latest" {
+			return o.baseURL(), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s is not pulled (run `ollama pull %s`)", ErrModelMissing, o.model(), o.model())
+}
+
+func (o *Ollama) Complete(ctx context.Context, system, prompt string) (string, error) {
+	req := map[string]any{
+		"model":  o.model(),
+		"stream": false,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+	}
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+	if err := postJSON(ctx, o.endpoint(), nil, req, &resp); err != nil {
+		return "", fmt.Errorf("ollama summarize: %w", err)
+	}
+	return resp.Message.Content, nil
+}
+
+// Stream reads Ollama's newline-delimited JSON responses.
+func (o *Ollama) Stream(ctx context.Context, system, prompt string, onChunk func(string)) (string, error) {
+	req := map[string]any{
+		"model":  o.model(),
+		"stream": true,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	var b strings.Builder
+	err := postStream(ctx, o.endpoint(), nil, req, func(line string) error {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		var ev struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			return nil
+		}
+		if ev.Error != "" {
+			return fmt.Errorf("%s", ev.Error)
+		}
+		if ev.Message.Content != "" {
+			b.WriteString(ev.Message.Content)
+			onChunk(ev.Message.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("ollama summarize: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	openAIDefaultURL   = "https://api.openai.com/v1"
+	openAIDefaultModel = "gpt-4o-mini"
+)
+
+// OpenAI calls any OpenAI-compatible chat completions endpoint.
+type OpenAI struct {
+	APIKey  string
+	Model   string
+	BaseURL string // e.g. https://api.openai.com/v1 or a local proxy
+}
+
+func (o *OpenAI) model() string {
+	if o.Model != "" {
+		return o.Model
+	}
+	return openAIDefaultModel
+}
+
+func (o *OpenAI) Name() string { return ProviderOpenAI + "/" + o.model() }
+
+func (o *OpenAI) baseURL() string {
+	base := o.BaseURL
+	if base == "" {
+		base = openAIDefaultURL
+	}
+	return strings.TrimRight(base, "/")
+}
+
+func (o *OpenAI) endpoint() string { return o.baseURL() + "/chat/completions" }
+
+// Check lists the available models, which needs a valid key.
+func (o *OpenAI) Check(ctx context.Context) (string, error) {
+	var resp struct{}
+	if err := getJSON(ctx, o.baseURL()+"/models", o.headers(), &resp); err != nil {
+		return "", err
+	}
+	return o.baseURL(), nil
+}
+
+func (o *OpenAI) headers() map[string]string {
+	headers := map[string]string{}
+	if o.APIKey != "" {
+		headers["Authorization"] = "Bearer " + o.APIKey
+	}
+	return headers
+}
+
+func (o *OpenAI) Complete(ctx context.Context, system, prompt string) (string, error) {
+	req := map[string]any{
+		"model": o.model(),
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+	}
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, o.endpoint(), o.headers(), req, &resp); err != nil {
+		return "", fmt.Errorf("openai summarize: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openai summarize: empty response")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Stream uses chat completions with "stream": true (server-sent events).
+func (o *OpenAI) Stream(ctx context.Context, system, prompt string, onChunk func(string)) (string, error) {
+	req := map[string]any{
+		"model":  o.model(),
+		"stream": true,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	var b strings.Builder
+	err := postStream(ctx, o.endpoint(), o.headers(), req, func(line string) error {
+		data, ok := sseData(line)
+		if !ok || data == "[DONE]" {
+			return nil
+		}
+		var ev struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &ev); err != nil || len(ev.Choices) == 0 {
+			return nil
+		}
+		if text := ev.Choices[0].Delta.Content; text != "" {
+			b.WriteString(text)
+			onChunk(text)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("openai summarize: %w", err)
+	}
+	return b.String(), nil
+}
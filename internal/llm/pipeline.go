@@ -0,0 +1,355 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eddy/pr-news/internal/retry"
+)
+
+// DefaultTokenBudget is the prompt size above which the pipeline switches
+// to map-reduce. It leaves headroom below common 128k-200k context windows.
+const DefaultTokenBudget = 100_000
+
+// Summary modes reported in Result.Mode and the report footer.
+const (
+	ModeSinglePass = "single-pass"
+	ModeMapReduce  = "map-reduce"
+)
+
+// chunkSeparator joins per-PR chunks inside a prompt.
+const chunkSeparator = "\n---\n"
+
+// promptOverhead reserves room for the instructions around the PR data.
+const promptOverhead = 1_000
+
+// EstimateTokens roughly estimates the token count of s: about four bytes
+// per token for ASCII text and one token per non-ASCII rune (e.g. Hangul).
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// Result is the outcome of a pipeline run.
+type Result struct {
+	Summary string
+	Mode    string
+	Batches int // map batches; 1 in single-pass mode
+	Tokens  int // estimated tokens of the collected PR data
+}
+
+// Pipeline summarizes PR chunks, splitting them into batches when the
+// combined prompt would exceed TokenBudget.
+type Pipeline struct {
+	Summarizer  Summarizer
+	TokenBudget int
+
+	// Prompt, if set, is a text/template (see PromptData) that replaces the
+	// built-in prompt for the final report.
+	Prompt string
+
+	// Scope, if set, describes the path or owner filter the PRs were
+	// collected with; it is stated in the report header and footer.
+	Scope string
+
+	// Warnings are notes about incomplete input (e.g. a truncated PR
+	// list), listed as quotes just before the footer.
+	Warnings []string
+
+	// Retry bounds each LLM call and retries transient failures.
+	Retry retry.Policy
+
+	// OnChunk, if set, receives the final report as it is generated when
+	// the Summarizer supports streaming. Map-step output is not streamed.
+	OnChunk func(string)
+}
+
+func (p *Pipeline) budget() int {
+	if p.TokenBudget > 0 {
+		return p.TokenBudget
+	}
+	return DefaultTokenBudget
+}
+
+// Run produces the final four-section report for one repository, followed
+// by its appendix, any warnings and a footer naming the mode that was used.
+func (p *Pipeline) Run(ctx context.Context, r RepoData) (Result, error) {
+	res, err := p.summarizeRepo(ctx, r.Chunks, r.Repo, r.DateRange)
+	if err != nil {
+		return res, err
+	}
+	if r.Appendix != "" {
+		res.Summary += "\n\n" + r.Appendix
+		if p.OnChunk != nil && isStreamer(p.Summarizer) {
+			p.OnChunk("\n\n" + r.Appendix)
+		}
+	}
+	if w := p.warnings(); w != "" {
+		res.Summary += "\n\n" + w
+		if p.OnChunk != nil && isStreamer(p.Summarizer) {
+			p.OnChunk("\n\n" + w)
+		}
+	}
+	res.Summary += "\n\n" + footer(res, p.Scope)
+	return res, nil
+}
+
+// warnings renders p.Warnings as a markdown quote; "" if there are none.
+func (p *Pipeline) warnings() string {
+	lines := make([]string, len(p.Warnings))
+	for i, w := range p.Warnings {
+		lines[i] = "> ⚠️ " + w
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// RepoData is the collected input for one repository.
+type RepoData struct {
+	Repo      string
+	Chunks    []string // one per PR
+	DateRange string
+	Appendix  string // markdown added verbatim after the repo's summary
+}
+
+// RunDigest summarizes several repositories into one report: a section per
+// repo followed by a cross-repo "what affects everyone" section.
+func (p *Pipeline) RunDigest(ctx context.Context, repos []RepoData, dateRange string) (Result, error) {
+	total := Result{Mode: ModeSinglePass}
+	var (
+		b     strings.Builder
+		modes []string
+		notes []string
+	)
+	// 제목과 구분선도 스트림에 끼워 넣어 화면이 최종 보고서와 같게 보이도록 한다
+	stream := p.OnChunk != nil && isStreamer(p.Summarizer)
+	write := func(s string) {
+		b.WriteString(s)
+		if stream {
+			p.OnChunk(s)
+		}
+	}
+
+	write(fmt.Sprintf("# PR Digest (%s)\n\n", dateRange))
+	if p.Scope != "" {
+		write("> 범위: " + p.Scope + "\n\n")
+	}
+	for _, r := range repos {
+		res, err := p.summarizeRepo(ctx, r.Chunks, r.Repo, r.DateRange)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", r.Repo, err)
+		}
+		b.WriteString(res.Summary) // 본문은 이미 OnChunk로 스트리밍됨
+		if r.Appendix != "" {
+			write("\n\n" + r.Appendix)
+		}
+		write("\n\n")
+
+		notes = append(notes, res.Summary)
+		total.Tokens += res.Tokens
+		total.Batches += res.Batches
+		if res.Mode == ModeMapReduce {
+			total.Mode = ModeMapReduce
+			modes = append(modes, fmt.Sprintf("%s %s (%d개 배치)", r.Repo, res.Mode, res.Batches))
+		} else {
+			modes = append(modes, fmt.Sprintf("%s %s", r.Repo, res.Mode))
+		}
+	}
+
+	cross, err := p.complete(ctx, systemPrompt, crossRepoPrompt(notes, dateRange), p.OnChunk)
+	if err != nil {
+		return total, fmt.Errorf("cross-repo summary: %w", err)
+	}
+	b.WriteString(strings.TrimSpace(cross))
+	if w := p.warnings(); w != "" {
+		write("\n\n" + w)
+	}
+
+	total.Summary = b.String() + "\n\n" + fmt.Sprintf("---\n_요약 모드: %s (입력 약 %d tokens)_", strings.Join(modes, ", "), total.Tokens)
+	return total, nil
+}
+
+// summarizeRepo picks single-pass or map-reduce for one repository.
+func (p *Pipeline) summarizeRepo(ctx context.Context, chunks []string, repo, dateRange string) (Result, error) {
+	res := Result{Mode: ModeSinglePass, Batches: 1}
+	for _, c := range chunks {
+		res.Tokens += EstimateTokens(c)
+	}
+
+	var err error
+	if res.Tokens+promptOverhead <= p.budget() {
+		data := strings.Join(chunks, chunkSeparator)
+		prompt := summaryPrompt(data, repo, len(chunks), dateRange, p.Scope)
+		if p.Prompt != "" {
+			prompt, err = renderPrompt(p.Prompt, PromptData{Repo: repo, PRCount: len(chunks), DateRange: dateRange, PRData: data, Scope: p.Scope, Format: reportFormat(repo, dateRange, p.Scope)})
+			if err != nil {
+				return res, err
+			}
+		}
+		res.Summary, err = p.complete(ctx, systemPrompt, prompt, p.OnChunk)
+		res.Summary = strings.TrimSpace(res.Summary)
+	} else {
+		res.Mode = ModeMapReduce
+		res.Summary, res.Batches, err = p.mapReduce(ctx, chunks, repo, dateRange)
+	}
+	return res, err
+}
+
+func (p *Pipeline) mapReduce(ctx context.Context, chunks []string, repo, dateRange string) (string, int, error) {
+	batches := p.batch(chunks)
+
+	// map: 배치별 중간 요약
+	notes := make([]string, 0, len(batches))
+	for i, batch := range batches {
+		out, err := p.complete(ctx, systemPrompt, mapPrompt(batch, repo, i+1, len(batches)), nil)
+		if err != nil {
+			return "", len(batches), fmt.Errorf("map batch %d/%d: %w", i+1, len(batches), err)
+		}
+		notes = append(notes, strings.TrimSpace(out))
+	}
+
+	// 중간 요약이 여전히 예산을 넘으면 한 번 더 합친다
+	for len(notes) > 1 && EstimateTokens(strings.Join(notes, chunkSeparator))+promptOverhead > p.budget() {
+		groups := p.batch(notes)
+		if len(groups) == len(notes) {
+			break // 더 이상 줄일 수 없음
+		}
+		merged := make([]string, 0, len(groups))
+		for _, g := range groups {
+			out, err := p.complete(ctx, systemPrompt, mapPrompt(g, repo, 0, 0), nil)
+			if err != nil {
+				return "", len(batches), fmt.Errorf("merging notes: %w", err)
+			}
+			merged = append(merged, strings.TrimSpace(out))
+		}
+		notes = merged
+	}
+
+	// reduce: 최종 보고서
+	prompt := reducePrompt(notes, repo, len(chunks), dateRange, p.Scope)
+	if p.Prompt != "" {
+		var err error
+		prompt, err = renderPrompt(p.Prompt, PromptData{Repo: repo, PRCount: len(chunks), DateRange: dateRange, PRData: strings.Join(notes, chunkSeparator), Batched: true, Scope: p.Scope, Format: reportFormat(repo, dateRange, p.Scope)})
+		if err != nil {
+			return "", len(batches), err
+		}
+	}
+	out, err := p.complete(ctx, systemPrompt, prompt, p.OnChunk)
+	if err != nil {
+		return "", len(batches), fmt.Errorf("reduce: %w", err)
+	}
+	return strings.TrimSpace(out), len(batches), nil
+}
+
+// batch groups chunks greedily so each group fits the budget. A chunk that
+// alone exceeds the budget is truncated and sent on its own.
+func (p *Pipeline) batch(chunks []string) [][]string {
+	limit := max(p.budget()-promptOverhead, promptOverhead)
+	var (
+		batches [][]string
+		cur     []string
+		size    int
+	)
+	for _, c := range chunks {
+		t := EstimateTokens(c)
+		if t > limit {
+			c = truncateTokens(c, limit)
+			t = limit
+		}
+		if len(cur) > 0 && size+t > limit {
+			batches = append(batches, cur)
+			cur, size = nil, 0
+		}
+		cur = append(cur, c)
+		size += t
+	}
+	if len(cur) > 0 {
+		batches = append(batches, cur)
+	}
+	return batches
+}
+
+// truncateTokens cuts s to approximately limit tokens.
+func truncateTokens(s string, limit int) string {
+	const marker = "\n... (truncated)"
+	quarters := 0 // 1/4 토큰 단위: ASCII 1, 그 외 4
+	for i, r := range s {
+		if r < utf8.RuneSelf {
+			quarters++
+		} else {
+			quarters += 4
+		}
+		if quarters/4 >= limit {
+			return s[:i] + marker
+		}
+	}
+	return s
+}
+
+func crossRepoPrompt(summaries []string, dateRange string) string {
+	return fmt.Sprintf(`다음은 같은 기간(%s) 동안 여러 레포지토리에서 머지된 PR을 레포별로 요약한 내용입니다.
+
+---
+%s
+---
+
+레포 경계를 넘어 모든 팀원에게 영향을 주는 내용만 골라 정리해주세요.
+(공유 라이브러리/API 계약 변경, 공통 의존성 업그레이드, 여러 레포에 걸친 기능, 팀 공통 컨벤션, breaking changes 등)
+해당 내용이 없으면 "없음"이라고만 적어주세요.
+
+# 🌐 모두에게 영향을 주는 변경`, dateRange, strings.Join(summaries, chunkSeparator))
+}
+
+func mapPrompt(parts []string, repo string, n, total int) string {
+	what := "중간 요약"
+	if n > 0 {
+		what = fmt.Sprintf("PR 묶음 %d/%d", n, total)
+	}
+	return fmt.Sprintf(`다음은 %s 레포지토리의 최근 머지된 PR 데이터 중 일부(%s)입니다.
+나중에 다른 묶음과 합쳐 최종 보고서를 만들 예정이니, 아래 네 가지 관점별로 핵심만 bullet points로 정리해주세요.
+PR 번호는 반드시 유지하세요.
+
+- 주요 변경사항
+- 버그 수정
+- 학습 포인트 (리뷰 코멘트, 코드 패턴)
+- 주의사항 (breaking changes, 마이그레이션)
+
+---
+%s
+---`, repo, what, strings.Join(parts, chunkSeparator))
+}
+
+func reducePrompt(notes []string, repo string, prCount int, dateRange, scope string) string {
+	return fmt.Sprintf(`다음은 %s 레포지토리의 최근 머지된 PR %d개를 여러 묶음으로 나누어 정리한 중간 요약입니다.
+기간: %s%s
+중복을 합치고 중요도 순으로 정리하여 하나의 보고서로 만들어주세요.
+
+---
+%s
+---
+
+다음 섹션으로 요약해주세요:
+
+%s`, repo, prCount, dateRange, scopeNote(scope), strings.Join(notes, chunkSeparator), reportFormat(repo, dateRange, scope))
+}
+
+func footer(r Result, scope string) string {
+	var s string
+	if r.Mode == ModeMapReduce {
+		s = fmt.Sprintf("---\n_요약 모드: %s (%d개 배치, 입력 약 %d tokens)", r.Mode, r.Batches, r.Tokens)
+	} else {
+		s = fmt.Sprintf("---\n_요약 모드: %s (입력 약 %d tokens)", r.Mode, r.Tokens)
+	}
+	if scope != "" {
+		s += ", 범위: " + scope
+	}
+	return s + "_"
+}
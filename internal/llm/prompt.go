@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptData is the data available to a custom prompt template.
+type PromptData struct {
+	Repo      string
+	PRCount   int
+	DateRange string
+	PRData    string // PR chunks, or the intermediate notes when Batched
+	Batched   bool   // map-reduce: PRData holds per-batch notes, not raw PRs
+	Scope     string // path filter, e.g. "apps/api/**, !apps/api/legacy/**"; "" if none
+	Format    string // the default four-section report layout
+}
+
+// ParsePrompt parses a custom prompt template (text/template syntax).
+func ParsePrompt(text string) (*template.Template, error) {
+	t, err := template.New("prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("prompt template: %w", err)
+	}
+	return t, nil
+}
+
+// renderPrompt executes the custom template text with d.
+func renderPrompt(text string, d PromptData) (string, error) {
+	t, err := ParsePrompt(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("prompt template: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,159 @@
+package panel
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eddy/pr-news/internal/history"
+	"github.com/eddy/pr-news/internal/style"
+)
+
+// OpenReportMsg asks the app to show a saved report.
+type OpenReportMsg struct{ Report history.Report }
+
+// DiffReportsMsg asks the app to show the diff from Old to New.
+type DiffReportsMsg struct{ Old, New history.Report }
+
+// DeleteReportMsg asks the app to delete a saved report.
+type DeleteReportMsg struct{ ID string }
+
+// CloseHistoryMsg returns to the search panel.
+type CloseHistoryMsg struct{}
+
+// HistoryPanel lists saved reports.
+type HistoryPanel struct {
+	Reports []history.Report
+	cursor  int
+	marked  string // diff 기준으로 표시한 보고서 ID
+	confirm string // 삭제 확인 대기 중인 보고서 ID
+
+	Width  int
+	Height int
+}
+
+func NewHistoryPanel() HistoryPanel {
+	return HistoryPanel{}
+}
+
+func (p *HistoryPanel) SetReports(reports []history.Report) {
+	p.Reports = reports
+	p.confirm = ""
+	if p.cursor >= len(reports) {
+		p.cursor = max(0, len(reports)-1)
+	}
+}
+
+func (p *HistoryPanel) selected() (history.Report, bool) {
+	if len(p.Reports) == 0 {
+		return history.Report{}, false
+	}
+	return p.Reports[p.cursor], true
+}
+
+func (p HistoryPanel) Update(msg tea.Msg) (HistoryPanel, tea.Cmd) {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	key := km.String()
+	if key != "x" {
+		p.confirm = ""
+	}
+
+	switch key {
+	case "esc":
+		return p, func() tea.Msg { return CloseHistoryMsg{} }
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.Reports)-1 {
+			p.cursor++
+		}
+	case "enter":
+		if r, ok := p.selected(); ok {
+			return p, func() tea.Msg { return OpenReportMsg{Report: r} }
+		}
+	case "d":
+		r, ok := p.selected()
+		if !ok {
+			break
+		}
+		if p.marked == "" || p.marked == r.ID {
+			// 첫 번째 선택: diff 기준 표시 (같은 항목이면 해제)
+			if p.marked == r.ID {
+				p.marked = ""
+			} else {
+				p.marked = r.ID
+			}
+			break
+		}
+		for _, old := range p.Reports {
+			if old.ID == p.marked {
+				p.marked = ""
+				return p, func() tea.Msg { return DiffReportsMsg{Old: old, New: r} }
+			}
+		}
+		p.marked = r.ID
+	case "x":
+		r, ok := p.selected()
+		if !ok {
+			break
+		}
+		if p.confirm != r.ID {
+			p.confirm = r.ID
+			break
+		}
+		p.confirm = ""
+		if p.marked == r.ID {
+			p.marked = ""
+		}
+		return p, func() tea.Msg { return DeleteReportMsg{ID: r.ID} }
+	}
+	return p, nil
+}
+
+func (p HistoryPanel) View() string {
+	var b strings.Builder
+
+	b.WriteString(style.PanelTitle.Render("History") + "\n")
+
+	if len(p.Reports) == 0 {
+		b.WriteString(style.StatusText.Render("No saved reports yet.") + "\n\n")
+		b.WriteString(style.HelpStyle.Render("Esc back"))
+		return b.String()
+	}
+
+	maxVisible := max(p.Height-5, 3)
+	start := 0
+	if p.cursor >= maxVisible {
+		start = p.cursor - maxVisible + 1
+	}
+	for i := start; i < len(p.Reports) && i < start+maxVisible; i++ {
+		r := p.Reports[i]
+		mark := "  "
+		if r.ID == p.marked {
+			mark = style.CursorStyle.Render("* ")
+		}
+		if i == p.cursor {
+			b.WriteString(style.CursorStyle.Render("> ") + mark + style.SelectedItem.Render(r.Title()) + "\n")
+		} else {
+			b.WriteString("  " + mark + style.UnselectedItem.Render(r.Title()) + "\n")
+		}
+	}
+	b.WriteString(style.StatusText.Render(fmt.Sprintf("  %d reports", len(p.Reports))) + "\n\n")
+
+	switch {
+	case p.confirm != "":
+		b.WriteString(style.ErrorText.Render("Press x again to delete"))
+	case p.marked != "":
+		b.WriteString(style.HelpStyle.Render("d diff with *  Enter open  Esc back"))
+	default:
+		b.WriteString(style.HelpStyle.Render("Enter open  d mark/diff  x delete  Esc back"))
+	}
+
+	return b.String()
+}
@@ -2,6 +2,8 @@ package panel
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -14,55 +16,99 @@ type FocusField int
 
 const (
 	FocusFilter FocusField = iota
+	FocusProfile
 	FocusDays
+	FocusSince
 	FocusBranch
+	FocusPaths
 	FocusFieldCount
 )
 
 // StartSearchMsg is sent when the user completes all fields and presses Enter.
 type StartSearchMsg struct{}
 
+// Profile is a config profile as offered by the profile picker.
+type Profile struct {
+	Name   string
+	Days   int
+	Branch string
+	Paths  string                 // path scope in github.ParseScope form
+	Repos  []string               // concrete repos preselected when picked
+	Match  func(repo string) bool // auto-selection
+}
+
+// Profile picker positions before the named profiles.
+const (
+	profileAuto = iota // follow the repos under the cursor / selected
+	profileNone
+	profileFixed // Profiles[i-profileFixed]
+)
+
 type InputPanel struct {
 	Repos    []string
 	filtered []string
 	cursor   int
+	selected map[string]bool // space로 여러 레포 선택
 	Loading  bool
 
 	Filter textinput.Model
 	Days   textinput.Model
 	Branch textinput.Model
+	Paths  textinput.Model // path scope, e.g. "apps/api/**, !apps/api/legacy/**"
 	focus  FocusField
 
+	// SinceLast uses the repo's last-run mark instead of Days.
+	SinceLast bool
+
+	Profiles []Profile
+	profile  int     // picker position (profileAuto, profileNone, ...)
+	lastAuto string  // profile last applied by auto-selection
+	defaults Profile // days, branch and paths without a profile
+
 	spinner spinner.Model
 
 	Width  int
 	Height int
 }
 
-func NewInputPanel() InputPanel {
+// NewInputPanel returns the search panel with Days, Branch and Paths preset from
+// defaults (7 days if unset).
+func NewInputPanel(defaults Profile) InputPanel {
+	if defaults.Days <= 0 {
+		defaults.Days = 7
+	}
+	days := defaults.Days
 	filter := textinput.New()
 	filter.Placeholder = "type to filter..."
 	filter.Focus()
 
-	days := textinput.New()
-	days.Placeholder = "7"
-	days.SetValue("7")
-	days.CharLimit = 4
+	daysInput := textinput.New()
+	daysInput.Placeholder = strconv.Itoa(days)
+	daysInput.SetValue(strconv.Itoa(days))
+	daysInput.CharLimit = 4
 
 	branch := textinput.New()
 	branch.Placeholder = "all branches"
+	branch.SetValue(defaults.Branch)
+
+	paths := textinput.New()
+	paths.Placeholder = "all paths (e.g. apps/api/**, !docs/)"
+	paths.SetValue(defaults.Paths)
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = style.CursorStyle
 
 	return InputPanel{
-		Filter:  filter,
-		Days:    days,
-		Branch:  branch,
-		focus:   FocusFilter,
-		Loading: true,
-		spinner: s,
+		Filter:   filter,
+		Days:     daysInput,
+		Branch:   branch,
+		Paths:    paths,
+		focus:    FocusFilter,
+		selected: map[string]bool{},
+		defaults: defaults,
+		Loading:  true,
+		spinner:  s,
 	}
 }
 
@@ -70,6 +116,7 @@ func (p *InputPanel) SetRepos(repos []string) {
 	p.Repos = repos
 	p.Loading = false
 	p.applyFilter()
+	p.syncAuto()
 }
 
 func (p *InputPanel) applyFilter() {
@@ -96,20 +143,136 @@ func (p *InputPanel) SelectedRepo() string {
 	return p.filtered[p.cursor]
 }
 
+// SelectedRepos returns the repos marked with space, in list order, or the
+// repo under the cursor when none are marked.
+func (p *InputPanel) SelectedRepos() []string {
+	var repos []string
+	for _, r := range p.Repos {
+		if p.selected[r] {
+			repos = append(repos, r)
+		}
+	}
+	if len(repos) == 0 {
+		if r := p.SelectedRepo(); r != "" {
+			repos = append(repos, r)
+		}
+	}
+	return repos
+}
+
+func (p *InputPanel) toggleSelected() {
+	r := p.SelectedRepo()
+	if r == "" {
+		return
+	}
+	if p.selected[r] {
+		delete(p.selected, r)
+	} else {
+		p.selected[r] = true
+	}
+}
+
 func (p *InputPanel) focusNext() {
 	p.focus = (p.focus + 1) % FocusFieldCount
+	if p.focus == FocusProfile && len(p.Profiles) == 0 {
+		p.focus++
+	}
 	p.syncFocus()
 }
 
 func (p *InputPanel) focusPrev() {
 	p.focus = (p.focus - 1 + FocusFieldCount) % FocusFieldCount
+	if p.focus == FocusProfile && len(p.Profiles) == 0 {
+		p.focus--
+	}
 	p.syncFocus()
 }
 
+// Profile returns the profile to run with: the picked one, the one matching
+// the selected repos in auto mode, or "".
+func (p *InputPanel) Profile() string {
+	switch {
+	case p.profile >= profileFixed:
+		return p.Profiles[p.profile-profileFixed].Name
+	case p.profile == profileAuto:
+		return p.autoProfile()
+	}
+	return ""
+}
+
+func (p *InputPanel) autoProfile() string {
+	repos := p.SelectedRepos()
+	if len(repos) == 0 {
+		return ""
+	}
+	for _, pr := range p.Profiles {
+		all := true
+		for _, r := range repos {
+			all = all && pr.Match != nil && pr.Match(r)
+		}
+		if all {
+			return pr.Name
+		}
+	}
+	return ""
+}
+
+// cycleProfile moves the picker by delta and applies a picked profile's
+// days, branch and repos.
+func (p *InputPanel) cycleProfile(delta int) {
+	n := len(p.Profiles) + profileFixed
+	p.profile = (p.profile + delta + n) % n
+	if p.profile < profileFixed {
+		p.applyProfile(p.defaults)
+		p.lastAuto = ""
+		p.syncAuto()
+		return
+	}
+	pr := p.Profiles[p.profile-profileFixed]
+	p.applyProfile(pr)
+	if len(pr.Repos) > 0 {
+		clear(p.selected)
+		for _, r := range pr.Repos {
+			if slices.Contains(p.Repos, r) {
+				p.selected[r] = true
+			}
+		}
+	}
+}
+
+// syncAuto applies the auto-selected profile when it changes.
+func (p *InputPanel) syncAuto() {
+	if p.profile != profileAuto {
+		return
+	}
+	name := p.autoProfile()
+	if name == p.lastAuto {
+		return
+	}
+	p.lastAuto = name
+	if name == "" {
+		p.applyProfile(p.defaults)
+	}
+	for _, pr := range p.Profiles {
+		if pr.Name == name {
+			p.applyProfile(pr)
+		}
+	}
+}
+
+func (p *InputPanel) applyProfile(pr Profile) {
+	if pr.Days > 0 {
+		p.Days.SetValue(strconv.Itoa(pr.Days))
+	}
+	p.Branch.SetValue(pr.Branch)
+	p.Paths.SetValue(pr.Paths)
+}
+
 func (p *InputPanel) syncFocus() {
 	p.Filter.Blur()
 	p.Days.Blur()
 	p.Branch.Blur()
+	p.Paths.Blur()
 	switch p.focus {
 	case FocusFilter:
 		p.Filter.Focus()
@@ -117,6 +280,8 @@ func (p *InputPanel) syncFocus() {
 		p.Days.Focus()
 	case FocusBranch:
 		p.Branch.Focus()
+	case FocusPaths:
+		p.Paths.Focus()
 	}
 }
 
@@ -143,18 +308,48 @@ func (p InputPanel) Update(msg tea.Msg) (InputPanel, tea.Cmd) {
 			p.focusPrev()
 			return p, tea.Batch(cmds...)
 		case "up", "k":
+			if km.String() == "k" && p.focus != FocusFilter {
+				break // 다른 입력란에서는 글자로 입력한다
+			}
 			if p.focus == FocusFilter && p.cursor > 0 {
 				p.cursor--
+				p.syncAuto()
 			}
 			return p, tea.Batch(cmds...)
 		case "down", "j":
+			if km.String() == "j" && p.focus != FocusFilter {
+				break // 다른 입력란에서는 글자로 입력한다
+			}
 			if p.focus == FocusFilter && p.cursor < len(p.filtered)-1 {
 				p.cursor++
+				p.syncAuto()
 			}
 			return p, tea.Batch(cmds...)
+		case "left", "right":
+			if p.focus == FocusProfile {
+				if km.String() == "left" {
+					p.cycleProfile(-1)
+				} else {
+					p.cycleProfile(1)
+				}
+				return p, tea.Batch(cmds...)
+			}
+		case " ":
+			switch p.focus {
+			case FocusFilter:
+				p.toggleSelected()
+				p.syncAuto()
+				return p, tea.Batch(cmds...)
+			case FocusProfile:
+				p.cycleProfile(1)
+				return p, tea.Batch(cmds...)
+			case FocusSince:
+				p.SinceLast = !p.SinceLast
+				return p, tea.Batch(cmds...)
+			}
 		case "enter":
 			// Enter advances to next field; on last field, trigger search
-			if p.focus < FocusBranch {
+			if p.focus < FocusPaths {
 				p.focusNext()
 				return p, tea.Batch(cmds...)
 			}
@@ -169,12 +364,16 @@ func (p InputPanel) Update(msg tea.Msg) (InputPanel, tea.Cmd) {
 		p.Filter, cmd = p.Filter.Update(msg)
 		cmds = append(cmds, cmd)
 		p.applyFilter()
+		p.syncAuto()
 	case FocusDays:
 		p.Days, cmd = p.Days.Update(msg)
 		cmds = append(cmds, cmd)
 	case FocusBranch:
 		p.Branch, cmd = p.Branch.Update(msg)
 		cmds = append(cmds, cmd)
+	case FocusPaths:
+		p.Paths, cmd = p.Paths.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return p, tea.Batch(cmds...)
@@ -196,7 +395,7 @@ func (p InputPanel) View() string {
 	if p.Loading {
 		b.WriteString(p.spinner.View() + " " + style.StatusText.Render("Loading repositories...") + "\n")
 	} else {
-		maxVisible := p.Height - 10
+		maxVisible := p.Height - 11
 		if maxVisible < 3 {
 			maxVisible = 3
 		}
@@ -207,27 +406,71 @@ func (p InputPanel) View() string {
 		}
 
 		for i := start; i < len(p.filtered) && i < start+maxVisible; i++ {
+			mark := "  "
+			if p.selected[p.filtered[i]] {
+				mark = style.CursorStyle.Render("✓ ")
+			}
 			if i == p.cursor {
-				b.WriteString(style.CursorStyle.Render("> ") + style.SelectedItem.Render(p.filtered[i]) + "\n")
+				b.WriteString(style.CursorStyle.Render("> ") + mark + style.SelectedItem.Render(p.filtered[i]) + "\n")
 			} else {
-				b.WriteString("  " + style.UnselectedItem.Render(p.filtered[i]) + "\n")
+				b.WriteString("  " + mark + style.UnselectedItem.Render(p.filtered[i]) + "\n")
 			}
 		}
 		if len(p.filtered) == 0 && len(p.Repos) > 0 {
 			b.WriteString(style.StatusText.Render("  (no match)") + "\n")
 		}
 		if len(p.filtered) > 0 {
-			b.WriteString(style.StatusText.Render(fmt.Sprintf("  %d/%d repos", len(p.filtered), len(p.Repos))) + "\n")
+			count := fmt.Sprintf("  %d/%d repos", len(p.filtered), len(p.Repos))
+			if n := len(p.selected); n > 0 {
+				count += fmt.Sprintf(", %d selected", n)
+			}
+			b.WriteString(style.StatusText.Render(count) + "\n")
 		}
 	}
 
 	b.WriteString("\n")
 
+	// Profile
+	if len(p.Profiles) > 0 {
+		var name string
+		switch {
+		case p.profile >= profileFixed:
+			name = p.Profiles[p.profile-profileFixed].Name
+		case p.profile == profileNone:
+			name = "none"
+		default:
+			name = "auto"
+			if auto := p.autoProfile(); auto != "" {
+				name += ": " + auto
+			}
+		}
+		if p.focus == FocusProfile {
+			b.WriteString(style.ActiveLabel.Render("Profile ") + "< " + name + " >" + style.HelpStyle.Render("  ←/→ change") + "\n")
+		} else {
+			b.WriteString(style.Label.Render("Profile ") + name + "\n")
+		}
+	}
+
 	// Days
+	days := p.Days.View()
+	if p.SinceLast {
+		days = style.StatusText.Render(p.Days.Value() + " (first run only)")
+	}
 	if p.focus == FocusDays {
-		b.WriteString(style.ActiveLabel.Render("Days    ") + p.Days.View() + "\n")
+		b.WriteString(style.ActiveLabel.Render("Days    ") + days + "\n")
 	} else {
-		b.WriteString(style.Label.Render("Days    ") + p.Days.View() + "\n")
+		b.WriteString(style.Label.Render("Days    ") + days + "\n")
+	}
+
+	// Since last run
+	check := "[ ] since last run"
+	if p.SinceLast {
+		check = "[x] since last run"
+	}
+	if p.focus == FocusSince {
+		b.WriteString(style.ActiveLabel.Render("Since   ") + check + style.HelpStyle.Render("  space toggle") + "\n")
+	} else {
+		b.WriteString(style.Label.Render("Since   ") + check + "\n")
 	}
 
 	// Branch
@@ -237,8 +480,15 @@ func (p InputPanel) View() string {
 		b.WriteString(style.Label.Render("Branch  ") + p.Branch.View() + "\n")
 	}
 
+	// Paths
+	if p.focus == FocusPaths {
+		b.WriteString(style.ActiveLabel.Render("Paths   ") + p.Paths.View() + "\n")
+	} else {
+		b.WriteString(style.Label.Render("Paths   ") + p.Paths.View() + "\n")
+	}
+
 	b.WriteString("\n")
-	b.WriteString(style.HelpStyle.Render("Enter next  Tab skip  Ctrl+C quit"))
+	b.WriteString(style.HelpStyle.Render("Enter next  Space select  Tab skip  Ctrl+O history  Ctrl+C quit"))
 
 	return b.String()
 }
@@ -0,0 +1,58 @@
+package panel
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeText sends s to p one key at a time.
+func typeText(p InputPanel, s string) InputPanel {
+	for _, r := range s {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg.Type = tea.KeySpace
+		}
+		p, _ = p.Update(msg)
+	}
+	return p
+}
+
+func TestInputPanelTextFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		focus FocusField
+		text  string
+		field func(InputPanel) string
+	}{
+		{"paths", FocusPaths, "packages/shared/**", func(p InputPanel) string { return p.Paths.Value() }},
+		{"paths with exclusion", FocusPaths, "apps/api/**, !apps/api/legacy/**", func(p InputPanel) string { return p.Paths.Value() }},
+		{"branch", FocusBranch, "jk-hotfix", func(p InputPanel) string { return p.Branch.Value() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewInputPanel(Profile{})
+			p.focus = tt.focus
+			p.syncFocus()
+			p.Paths.SetValue("")
+			p.Branch.SetValue("")
+			p = typeText(p, tt.text)
+			if got := tt.field(p); got != tt.text {
+				t.Errorf("value = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestInputPanelFilterNavigatesWithJK(t *testing.T) {
+	p := NewInputPanel(Profile{})
+	p.SetRepos([]string{"o/a", "o/b", "o/c"})
+	p = typeText(p, "jj")
+	if p.cursor != 2 {
+		t.Errorf("cursor after jj = %d, want 2", p.cursor)
+	}
+	p = typeText(p, "k")
+	if p.cursor != 1 || p.Filter.Value() != "" {
+		t.Errorf("after k: cursor %d, filter %q; want 1, empty", p.cursor, p.Filter.Value())
+	}
+}
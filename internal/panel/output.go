@@ -3,18 +3,21 @@ package panel
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
+	"github.com/eddy/pr-news/internal/doctor"
 	"github.com/eddy/pr-news/internal/style"
 )
 
 type OutputState int
 
 const (
-	OutputLoading OutputState = iota
+	OutputPreflight OutputState = iota
+	OutputLoading
 	OutputIdle
 	OutputFetching
 	OutputSummarizing
@@ -30,14 +33,35 @@ type OutputPanel struct {
 	RawContent string // 원본 마크다운 (클립보드용)
 	CopyMsg    string // "Copied!" 메시지 (일시적)
 	Error      string
+	Fix        string        // 오류 해결 방법 (error 상태에 표시)
+	Log        []string      // 진행 로그 (수집 단계)
+	Hint       string        // idle 상태 안내 (기본 문구 대체)
+	Keys       string        // done 상태 키 도움말 (기본 문구 대체)
+	Warnings   []string      // 불완전한 데이터 경고 (idle/수집 단계에 표시)
+	Notice     string        // 일시적인 대기 상태 (예: 레이트 리밋)
+	Retry      string        // 재시도 중인 호출 (진행 줄 옆에 표시)
+	Checks     doctor.Report // 시작 점검 결과 (실패한 경우만, 점검 중이면 nil)
 
 	spinner  spinner.Model
 	viewport viewport.Model
 	Width    int
 	Height   int
 	ready    bool
+
+	// 스트리밍 중인 부분 마크다운
+	stream       string
+	follow       bool // 새 청크가 오면 맨 아래로 스크롤
+	lastRender   time.Time
+	flushPending bool // 건너뛴 렌더링을 위한 tick이 예약됨
 }
 
+// streamRenderInterval throttles glamour re-rendering while streaming.
+const streamRenderInterval = 80 * time.Millisecond
+
+// streamFlushMsg renders chunks that arrived within streamRenderInterval
+// of the previous render.
+type streamFlushMsg struct{}
+
 func NewOutputPanel() OutputPanel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -61,7 +85,56 @@ func (p *OutputPanel) SetSize(w, h int) {
 	}
 }
 
+// ResetStream clears any partial output before a new summary starts.
+func (p *OutputPanel) ResetStream() {
+	p.stream = ""
+	p.follow = true
+	p.flushPending = false
+	if p.ready {
+		p.viewport.SetContent("")
+		p.viewport.GotoTop()
+	}
+}
+
+// Streaming reports whether partial output has arrived.
+func (p *OutputPanel) Streaming() bool {
+	return p.stream != ""
+}
+
+// AppendStream adds a chunk of generated markdown and re-renders the
+// viewport, keeping it pinned to the bottom unless the user scrolled up.
+// Within streamRenderInterval of the last render it instead returns a
+// tick that renders the pending chunks.
+func (p *OutputPanel) AppendStream(chunk string) tea.Cmd {
+	p.stream += chunk
+	if !p.ready {
+		return nil
+	}
+	if wait := streamRenderInterval - time.Since(p.lastRender); wait > 0 {
+		if p.flushPending {
+			return nil
+		}
+		p.flushPending = true
+		return tea.Tick(wait, func(time.Time) tea.Msg { return streamFlushMsg{} })
+	}
+	p.renderStream()
+	return nil
+}
+
+func (p *OutputPanel) renderStream() {
+	p.lastRender = time.Now()
+	rendered, err := glamour.Render(p.stream, "dark")
+	if err != nil {
+		rendered = p.stream
+	}
+	p.viewport.SetContent(rendered)
+	if p.follow {
+		p.viewport.GotoBottom()
+	}
+}
+
 func (p *OutputPanel) SetContent(md string) {
+	p.stream = ""
 	p.RawContent = md // 원본 저장 (클립보드용)
 	rendered, err := glamour.Render(md, "dark")
 	if err != nil {
@@ -73,10 +146,27 @@ func (p *OutputPanel) SetContent(md string) {
 	}
 }
 
+// AddLog appends a progress line shown under the status while working.
+func (p *OutputPanel) AddLog(line string) {
+	p.Log = append(p.Log, line)
+}
+
+func (p *OutputPanel) ClearLog() {
+	p.Log = nil
+}
+
 func (p OutputPanel) Update(msg tea.Msg) (OutputPanel, tea.Cmd) {
+	if _, ok := msg.(streamFlushMsg); ok {
+		p.flushPending = false
+		if p.ready && p.stream != "" {
+			p.renderStream()
+		}
+		return p, nil
+	}
+
 	var cmds []tea.Cmd
 
-	if p.State == OutputLoading || p.State == OutputFetching || p.State == OutputSummarizing {
+	if p.State == OutputPreflight || p.State == OutputLoading || p.State == OutputFetching || p.State == OutputSummarizing {
 		var cmd tea.Cmd
 		p.spinner, cmd = p.spinner.Update(msg)
 		cmds = append(cmds, cmd)
@@ -88,6 +178,27 @@ func (p OutputPanel) Update(msg tea.Msg) (OutputPanel, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	// 스트리밍 중 스크롤: 위로 올리면 자동 스크롤 중지, 맨 아래로 내리면 재개
+	if p.State == OutputSummarizing && p.ready && p.stream != "" {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if s := msg.String(); s == "G" || s == "end" {
+				p.viewport.GotoBottom()
+				p.follow = true
+				break
+			}
+			var cmd tea.Cmd
+			p.viewport, cmd = p.viewport.Update(msg)
+			cmds = append(cmds, cmd)
+			p.follow = p.viewport.AtBottom()
+		case tea.MouseMsg:
+			var cmd tea.Cmd
+			p.viewport, cmd = p.viewport.Update(msg)
+			cmds = append(cmds, cmd)
+			p.follow = p.viewport.AtBottom()
+		}
+	}
+
 	return p, tea.Batch(cmds...)
 }
 
@@ -95,37 +206,123 @@ func (p OutputPanel) Init() tea.Cmd {
 	return p.spinner.Tick
 }
 
+func (p OutputPanel) warningsView() string {
+	lines := make([]string, len(p.Warnings))
+	for i, w := range p.Warnings {
+		lines[i] = "⚠ " + w
+	}
+	return style.WarningText.Render(strings.Join(lines, "\n"))
+}
+
+// checksView renders the preflight checklist with a fix under each check
+// that needs one.
+func (p OutputPanel) checksView() string {
+	var b strings.Builder
+	for _, c := range p.Checks {
+		mark := style.SuccessText
+		switch c.Status {
+		case doctor.Skipped:
+			mark = style.StatusText
+		case doctor.Warning:
+			mark = style.WarningText
+		case doctor.Failed:
+			mark = style.ErrorText
+		}
+		fmt.Fprintf(&b, "%s %s  %s\n", mark.Render(c.Status.Mark()), style.Label.Render(c.Name), style.StatusText.Render(c.Detail))
+		if c.Hint != "" && (c.Status == doctor.Warning || c.Status == doctor.Failed) {
+			b.WriteString("  " + style.WarningText.Render("Fix: "+c.Hint) + "\n")
+		}
+	}
+	return b.String()
+}
+
 func (p OutputPanel) View() string {
 	var b strings.Builder
 
 	b.WriteString(style.PanelTitle.Render("Output") + "\n")
 
 	switch p.State {
+	case OutputPreflight:
+		if p.Checks == nil {
+			b.WriteString(p.spinner.View() + " " + style.StatusText.Render("Checking gh, GitHub auth and the LLM provider..."))
+			break
+		}
+		b.WriteString(p.checksView() + "\n")
+		b.WriteString(style.HelpStyle.Render("r recheck  enter continue anyway  q quit"))
+
 	case OutputLoading:
 		b.WriteString(p.spinner.View() + " " + style.StatusText.Render("Loading repositories..."))
+		if p.Notice != "" {
+			b.WriteString("\n" + style.WarningText.Render(p.Notice))
+		}
 
 	case OutputIdle:
-		b.WriteString(style.StatusText.Render("Select a repository and press Enter to start."))
+		hint := "Select a repository and press Enter to start."
+		if p.Hint != "" {
+			hint = p.Hint
+		}
+		b.WriteString(style.StatusText.Render(hint))
+		if len(p.Warnings) > 0 {
+			b.WriteString("\n\n" + p.warningsView())
+		}
 
 	case OutputFetching, OutputSummarizing:
-		b.WriteString(p.spinner.View() + " " + p.Status + "\n")
-		if p.Progress != "" {
-			b.WriteString(style.StatusText.Render(p.Progress))
+		if p.State == OutputSummarizing && p.stream != "" && p.ready {
+			b.WriteString(p.viewport.View() + "\n")
+			help := "j/k scroll  following  esc cancel"
+			if !p.follow {
+				help = "j/k scroll  G follow  esc cancel"
+			}
+			b.WriteString(p.spinner.View() + " " + style.HelpStyle.Render(help))
+			if p.Retry != "" {
+				b.WriteString("  " + style.WarningText.Render(p.Retry))
+			}
+			break
+		}
+		b.WriteString(p.spinner.View() + " " + p.Status + "  " + style.HelpStyle.Render("esc cancel") + "\n")
+		if p.Progress != "" || p.Retry != "" {
+			var line []string
+			if p.Progress != "" {
+				line = append(line, style.StatusText.Render(p.Progress))
+			}
+			if p.Retry != "" {
+				line = append(line, style.WarningText.Render(p.Retry))
+			}
+			b.WriteString(strings.Join(line, "  ") + "\n")
+		}
+		if p.Notice != "" {
+			b.WriteString(style.WarningText.Render(p.Notice) + "\n")
+		}
+		if len(p.Warnings) > 0 {
+			b.WriteString("\n" + p.warningsView() + "\n")
+		}
+		if len(p.Log) > 0 {
+			// 패널 높이에 맞게 최근 로그만 표시
+			maxLines := max(p.Height-5-len(p.Warnings), 1)
+			start := max(len(p.Log)-maxLines, 0)
+			b.WriteString("\n" + style.StatusText.Render(strings.Join(p.Log[start:], "\n")))
 		}
 
 	case OutputDone:
 		if p.ready {
 			b.WriteString(p.viewport.View() + "\n")
-			help := fmt.Sprintf("j/k scroll  c copy  r restart  %d%%", int(p.viewport.ScrollPercent()*100))
+			keys := "j/k scroll  c copy  h history  r restart"
+			if p.Keys != "" {
+				keys = p.Keys
+			}
 			if p.CopyMsg != "" {
-				help = fmt.Sprintf("j/k scroll  %s  r restart  %d%%", p.CopyMsg, int(p.viewport.ScrollPercent()*100))
+				keys = strings.Replace(keys, "c copy", p.CopyMsg, 1)
 			}
+			help := fmt.Sprintf("%s  %d%%", keys, int(p.viewport.ScrollPercent()*100))
 			b.WriteString(style.HelpStyle.Render(help))
 		}
 
 	case OutputError:
 		b.WriteString(style.ErrorText.Render("Error: "+p.Error) + "\n\n")
-		b.WriteString(style.HelpStyle.Render("r retry  q quit"))
+		if p.Fix != "" {
+			b.WriteString(style.WarningText.Render("Fix: "+p.Fix) + "\n\n")
+		}
+		b.WriteString(style.HelpStyle.Render("r retry  h history  q quit"))
 	}
 
 	return b.String()
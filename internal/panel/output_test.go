@@ -0,0 +1,37 @@
+package panel
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var sgr = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestAppendStreamFlushesThrottledChunks(t *testing.T) {
+	p := NewOutputPanel()
+	p.SetSize(80, 20)
+	p.ResetStream()
+
+	if cmd := p.AppendStream("alpha"); cmd != nil {
+		t.Fatal("first chunk was not rendered right away")
+	}
+	cmd := p.AppendStream(" omega")
+	if cmd == nil {
+		t.Fatal("throttled chunk scheduled no flush")
+	}
+	if strings.Contains(p.viewport.View(), "omega") {
+		t.Fatal("throttled chunk rendered early")
+	}
+	if again := p.AppendStream("!"); again != nil {
+		t.Error("a second flush was scheduled while one is pending")
+	}
+
+	p, _ = p.Update(cmd())
+	if view := sgr.ReplaceAllString(p.viewport.View(), ""); !strings.Contains(view, "alpha omega!") {
+		t.Errorf("after flush the view lacks the last chunks:\n%s", view)
+	}
+	if p.flushPending {
+		t.Error("flush still pending after it ran")
+	}
+}
@@ -0,0 +1,144 @@
+// Package proc runs the external CLIs pr-news depends on (gh, claude) and
+// turns their failures into errors that keep stderr and say what to do.
+package proc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Failure kinds. A *Error matches its kind with errors.Is.
+var (
+	ErrNotInstalled     = errors.New("not installed")
+	ErrNotAuthenticated = errors.New("not authenticated")
+	ErrNotFound         = errors.New("not found")
+	ErrRateLimited      = errors.New("rate limited")
+)
+
+// Error is a failed run of Name.
+type Error struct {
+	Name   string
+	Args   []string
+	Err    error  // from os/exec, e.g. *exec.ExitError
+	Stderr string // trimmed; the end of stdout if stderr was empty
+	Kind   error  // one of the Err* kinds, or nil
+	Hint   string // remediation for the user; may be ""
+}
+
+func (e *Error) Error() string {
+	msg := e.Err.Error()
+	if e.Stderr != "" {
+		msg = firstLines(e.Stderr, 3, 300)
+	}
+	if e.Kind != nil && !strings.Contains(strings.ToLower(msg), e.Kind.Error()) {
+		msg = e.Kind.Error() + ": " + msg
+	}
+	return e.command() + ": " + msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is e's kind.
+func (e *Error) Is(target error) bool { return e.Kind != nil && target == e.Kind }
+
+// command names the run for messages: the binary and its leading
+// subcommand words, without flags or long arguments.
+func (e *Error) command() string {
+	words := []string{e.Name}
+	for _, a := range e.Args[:min(len(e.Args), 2)] {
+		if strings.HasPrefix(a, "-") || len(a) > 40 {
+			break
+		}
+		words = append(words, a)
+	}
+	return strings.Join(words, " ")
+}
+
+// Hint returns the remediation hint of the first *Error in err's chain.
+func Hint(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Hint
+	}
+	return ""
+}
+
+// Classifier sets e.Kind and e.Hint for a failed run of one CLI. e.Kind
+// is already ErrNotInstalled when the binary could not be found.
+type Classifier func(e *Error)
+
+// Cmd is one run of an external CLI.
+type Cmd struct {
+	Name     string
+	Args     []string
+	Stdin    io.Reader
+	Classify Classifier // optional
+}
+
+// Output runs c and returns its stdout. A run that fails returns a
+// *Error; one ended by ctx returns ctx's error.
+func (c Cmd) Output(ctx context.Context) ([]byte, error) {
+	var out bytes.Buffer
+	err := c.Stream(ctx, &out)
+	return out.Bytes(), err
+}
+
+// Stream runs c, copying its stdout to w as it is written. Errors are
+// as for Output.
+func (c Cmd) Stream(ctx context.Context, w io.Writer) error {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Stdin = c.Stdin
+	var stderr bytes.Buffer
+	var tail tailWriter // 일부 CLI는 오류를 stdout에 쓴다
+	cmd.Stdout = io.MultiWriter(w, &tail)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err() // 취소로 종료된 프로세스의 오류 대신
+	}
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		msg = strings.TrimSpace(string(tail.buf))
+	}
+	e := &Error{Name: c.Name, Args: c.Args, Err: err, Stderr: msg}
+	if errors.Is(err, exec.ErrNotFound) {
+		e.Kind = ErrNotInstalled
+	}
+	if c.Classify != nil {
+		c.Classify(e)
+	}
+	return e
+}
+
+// tailWriter keeps the last tailSize bytes written to it.
+type tailWriter struct{ buf []byte }
+
+const tailSize = 1024
+
+func (t *tailWriter) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > tailSize {
+		t.buf = t.buf[len(t.buf)-tailSize:]
+	}
+	return len(p), nil
+}
+
+// firstLines trims s to its first n lines and at most limit bytes.
+func firstLines(s string, n, limit int) string {
+	lines := strings.SplitN(s, "\n", n+1)
+	if len(lines) > n {
+		lines = append(lines[:n], "...")
+	}
+	s = strings.Join(lines, "\n")
+	if len(s) > limit {
+		s = s[:limit] + "..."
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+// Package retry runs calls with a per-attempt timeout and retries
+// transient failures with exponential backoff.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+)
+
+// Default backoff bounds.
+const (
+	DefaultBase = time.Second
+	DefaultMax  = 30 * time.Second
+)
+
+// Policy says how often and how long to try a call.
+type Policy struct {
+	Retries int           // extra attempts after the first
+	Timeout time.Duration // per attempt; 0 means none
+	Base    time.Duration // first backoff, doubled per retry; 0 uses DefaultBase
+	Max     time.Duration // backoff cap; 0 uses DefaultMax
+}
+
+// Attempt describes a retry about to happen.
+type Attempt struct {
+	What  string        // the call, e.g. "GitHub GET /repos/a/b/pulls/1"
+	N     int           // the attempt about to run, starting at 2
+	Of    int           // total attempts allowed
+	Err   error         // why the previous attempt failed
+	Delay time.Duration // backoff before attempt N
+}
+
+func (a Attempt) String() string {
+	return fmt.Sprintf("Retrying %s (%d/%d) in %s: %v", a.What, a.N, a.Of, a.Delay.Round(100*time.Millisecond), a.Err)
+}
+
+// TimeoutError reports that What ran longer than After.
+type TimeoutError struct {
+	What  string
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s", e.What, e.After)
+}
+
+// Is lets errors.Is(err, context.DeadlineExceeded) match.
+func (e *TimeoutError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+type notifyKey struct{}
+
+// WithNotify returns a context whose Do calls report each retry to f.
+func WithNotify(ctx context.Context, f func(Attempt)) context.Context {
+	return context.WithValue(ctx, notifyKey{}, f)
+}
+
+// Do calls fn until it succeeds, fails with an error transient rejects,
+// or p's attempts run out. An attempt that exceeds p.Timeout fails with a
+// *TimeoutError, which Transient accepts. Do stops with ctx's error once
+// ctx is done.
+func Do(ctx context.Context, p Policy, what string, transient func(error) bool, fn func(context.Context) error) error {
+	attempts := max(p.Retries, 0) + 1
+	for n := 1; ; n++ {
+		err := attempt(ctx, p.Timeout, fn)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, errAttemptTimeout) {
+			err = &TimeoutError{What: what, After: p.Timeout}
+		}
+		if n == attempts || !transient(err) {
+			return err
+		}
+
+		a := Attempt{What: what, N: n + 1, Of: attempts, Err: err, Delay: p.backoff(n)}
+		if f, ok := ctx.Value(notifyKey{}).(func(Attempt)); ok {
+			f(a)
+		}
+		t := time.NewTimer(a.Delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Transient reports whether err is a timeout or a network failure that
+// may not recur: a reset or refused connection, or a response cut short.
+func Transient(err error) bool {
+	var timeout *TimeoutError
+	var netErr net.Error
+	return errors.As(err, &timeout) || errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+var errAttemptTimeout = errors.New("attempt timed out")
+
+// attempt runs fn under its own deadline and reports errAttemptTimeout
+// when that deadline, rather than ctx's, ended it.
+func attempt(ctx context.Context, timeout time.Duration, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	actx, cancel := context.WithTimeoutCause(ctx, timeout, errAttemptTimeout)
+	defer cancel()
+	err := fn(actx)
+	if err != nil && ctx.Err() == nil && errors.Is(context.Cause(actx), errAttemptTimeout) {
+		return errAttemptTimeout
+	}
+	return err
+}
+
+// backoff returns the wait after failed attempt n: Base doubled per
+// retry, capped at Max, with up to 25% jitter so parallel workers spread
+// out.
+func (p Policy) backoff(n int) time.Duration {
+	base, limit := p.Base, p.Max
+	if base <= 0 {
+		base = DefaultBase
+	}
+	if limit <= 0 {
+		limit = DefaultMax
+	}
+	d := min(base<<(n-1), limit)
+	return d + rand.N(d/4+1)
+}
+
+// WithTimeout is context.WithTimeout for a stage limit; d <= 0 means none.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// StageError turns the deadline error of a stage that ran out of time,
+// whose context is ctx, into a *TimeoutError naming the stage; other
+// errors are returned unchanged.
+func StageError(ctx context.Context, what string, limit time.Duration, err error) error {
+	if err != nil && errors.Is(context.Cause(ctx), context.DeadlineExceeded) && errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{What: what, After: limit}
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Policy
+		n    int
+		want time.Duration // before jitter
+	}{
+		{"defaults", Policy{}, 1, DefaultBase},
+		{"doubles", Policy{Base: 100 * time.Millisecond}, 3, 400 * time.Millisecond},
+		{"capped", Policy{Base: time.Second, Max: 5 * time.Second}, 4, 5 * time.Second},
+		{"default cap", Policy{Base: time.Second}, 10, DefaultMax},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := tt.p.backoff(tt.n); got < tt.want || got > tt.want+tt.want/4 {
+				t.Errorf("%s: backoff(%d) = %s, want %s plus up to 25%%", tt.name, tt.n, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&TimeoutError{What: "x", After: time.Second}, true},
+		{fmt.Errorf("GET: %w", io.ErrUnexpectedEOF), true},
+		{fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{syscall.ECONNRESET, true},
+		{errors.New("404 Not Found"), false},
+		{context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := Transient(tt.err); got != tt.want {
+			t.Errorf("Transient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+var errFlaky = errors.New("flaky")
+
+func TestDo(t *testing.T) {
+	fast := Policy{Retries: 2, Base: time.Nanosecond}
+	isFlaky := func(err error) bool { return errors.Is(err, errFlaky) }
+	tests := []struct {
+		name     string
+		p        Policy
+		failures []error // returned by successive attempts, then nil
+		want     error
+		calls    int
+	}{
+		{"first try", fast, nil, nil, 1},
+		{"retried to success", fast, []error{errFlaky, errFlaky}, nil, 3},
+		{"attempts run out", fast, []error{errFlaky, errFlaky, errFlaky}, errFlaky, 3},
+		{"permanent error", fast, []error{io.EOF}, io.EOF, 1},
+		{"no retries", Policy{}, []error{errFlaky}, errFlaky, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			err := Do(context.Background(), tt.p, "call", isFlaky, func(context.Context) error {
+				calls++
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestDoAttemptTimeout(t *testing.T) {
+	var attempts []Attempt
+	ctx := WithNotify(context.Background(), func(a Attempt) { attempts = append(attempts, a) })
+	p := Policy{Retries: 1, Timeout: time.Millisecond, Base: time.Nanosecond}
+	err := Do(ctx, p, "slow call", Transient, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var te *TimeoutError
+	if !errors.As(err, &te) || te.What != "slow call" || te.After != time.Millisecond {
+		t.Fatalf("err = %v, want a *TimeoutError for slow call", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("TimeoutError does not match context.DeadlineExceeded")
+	}
+	if len(attempts) != 1 || attempts[0].N != 2 || attempts[0].Of != 2 {
+		t.Errorf("notified %+v, want one retry 2/2", attempts)
+	}
+}
+
+func TestDoStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	err := Do(ctx, Policy{Retries: 5, Base: time.Hour}, "call", Transient, func(context.Context) error {
+		calls++
+		cancel()
+		return io.ErrUnexpectedEOF
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want context.Canceled after 1", err, calls)
+	}
+}
+
+func TestStageError(t *testing.T) {
+	ctx, cancel := WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	err := StageError(ctx, "collecting", time.Minute, fmt.Errorf("listing: %w", ctx.Err()))
+	var te *TimeoutError
+	if !errors.As(err, &te) || te.Error() != "collecting timed out after 1m0s" {
+		t.Errorf("StageError = %v, want collecting timed out after 1m0s", err)
+	}
+
+	other := errors.New("boom")
+	if err := StageError(ctx, "collecting", time.Minute, other); err != other {
+		t.Errorf("StageError(other) = %v, want it unchanged", err)
+	}
+}
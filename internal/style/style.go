@@ -9,6 +9,7 @@ var (
 	Dim     = lipgloss.Color("#555555")
 	Text    = lipgloss.Color("#CDD6F4")
 	Red     = lipgloss.Color("#F38BA8")
+	Yellow  = lipgloss.Color("#FFCB6B")
 
 	// Panel borders — minimal padding
 	InputPanel = lipgloss.NewStyle().
@@ -29,6 +30,7 @@ var (
 	// Status
 	StatusText  = lipgloss.NewStyle().Foreground(Dim)
 	ErrorText   = lipgloss.NewStyle().Foreground(Red)
+	WarningText = lipgloss.NewStyle().Foreground(Yellow)
 	SuccessText = lipgloss.NewStyle().Foreground(Accent)
 
 	// List items
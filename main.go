@@ -1,16 +1,185 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/eddy/pr-news/internal/app"
+	"github.com/eddy/pr-news/internal/config"
+	"github.com/eddy/pr-news/internal/doctor"
+	"github.com/eddy/pr-news/internal/github"
+	"github.com/eddy/pr-news/internal/headless"
+	"github.com/eddy/pr-news/internal/history"
+	"github.com/eddy/pr-news/internal/llm"
 )
 
+// settingFlags maps flags that override a config setting to its key.
+var settingFlags = map[string]string{
+	"days":           "days",
+	"branch":         "branch",
+	"workers":        "workers",
+	"max-prs":        "max_prs",
+	"max-repos":      "max_repos",
+	"token-budget":   "token_budget",
+	"provider":       "llm.provider",
+	"model":          "llm.model",
+	"llm-url":        "llm.base_url",
+	"llm-timeout":    "llm.call_timeout",
+	"github-backend": "github.backend",
+	"github-url":     "github.api_url",
+	"github-timeout": "github.call_timeout",
+	"include":        "paths.include",
+	"exclude":        "paths.exclude",
+	"owner":          "owner",
+	"owner-mode":     "owner_mode",
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		os.Exit(headless.RunCache(os.Args[2:]))
+	}
+	// doctor는 설정 플래그를 그대로 받는다 (pr-news doctor --provider ollama)
+	runDoctor := len(os.Args) > 1 && os.Args[1] == "doctor"
+	if runDoctor {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
+	def := config.Default()
+	var opts headless.Options
+	configPath := flag.String("config", "", "config file to load instead of ~/.pr-news.conf and "+config.TOMLPath())
+	repos := flag.String("repo", "", "run headless against owner/name (comma-separated for a digest) instead of starting the TUI")
+	flag.Int("days", def.Days, "number of days to look back")
+	profile := flag.String("profile", "", "config profile to apply (headless; default: the profile whose repos match --repo)")
+	flag.String("branch", "", "base branch filter")
+	flag.String("include", "", "only summarize PRs touching these path globs (comma-separated, e.g. apps/api/**)")
+	flag.String("exclude", "", "ignore files matching these path globs (comma-separated)")
+	flag.String("owner", "", "CODEOWNERS owner (@user or @org/team) to report on")
+	flag.String("owner-mode", def.Limits.OwnerMode, "with --owner: filter (only PRs touching the owner's files) or group (all PRs, indexed by owner)")
+	flag.BoolVar(&opts.SinceLast, "since-last", false, "only PRs merged since the last successful run on this repo (headless)")
+	flag.StringVar(&opts.Out, "out", "", "write the summary to this file instead of stdout (headless)")
+	flag.Int("workers", def.Workers, "number of PRs to collect concurrently")
+	flag.Int("max-prs", def.MaxPRs, fmt.Sprintf("most recently merged PRs to fetch per repo (at most %d)", github.MaxSearchResults))
+	flag.Int("max-repos", def.MaxRepos, "repos per owner listed in the TUI's repo picker")
+	flag.Int("token-budget", def.TokenBudget, "estimated prompt tokens above which PRs are summarized in batches")
+
+	flag.String("provider", def.LLM.Provider, "LLM provider: claude-cli, anthropic, openai or ollama")
+	flag.String("model", "", "LLM model name (provider default if empty)")
+	flag.String("llm-url", "", "LLM API base URL (provider default if empty)")
+	flag.Duration("llm-timeout", def.LLM.CallTimeout, "limit for each LLM call before it is retried (0 for none)")
+
+	flag.String("github-backend", def.GitHub.Backend, "GitHub backend: auto, api or gh")
+	flag.String("github-url", "", "GitHub REST API base URL (e.g. https://ghe.example.com/api/v3)")
+	flag.Duration("github-timeout", def.GitHub.CallTimeout, "limit for each GitHub request or gh run before it is retried (0 for none)")
+	noCache := flag.Bool("no-cache", false, "do not read or write the PR data cache")
+	refresh := flag.Bool("refresh", false, "re-fetch PR data and overwrite the cache")
+	flag.Parse()
+
+	// 우선순위: 기본값 < 설정 파일 < 환경 변수 < 명시한 플래그
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: config: %v\n", err)
+		os.Exit(headless.ExitUsage)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		key, ok := settingFlags[f.Name]
+		if !ok || err != nil {
+			return
+		}
+		if e := cfg.Set(key, f.Value.String()); e != nil {
+			err = fmt.Errorf("-%s: %w", f.Name, e)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(headless.ExitUsage)
+	}
+
+	if runDoctor {
+		var opts doctor.Options
+		opts.Summarizer, opts.LLMErr = llm.New(cfg.LLM)
+		opts.GitHub, opts.GitHubErr = github.New(cfg.GitHub)
+		os.Exit(headless.RunDoctor(opts))
+	}
+
+	var cache *github.Cache
+	if !*noCache {
+		cache = &github.Cache{Dir: github.DefaultCacheDir(), Refresh: *refresh}
+	}
+
+	gh, ghErr := github.New(cfg.GitHub)
+
+	store := &history.Store{Dir: history.DefaultDir()}
+	marks := &history.Marks{Path: history.DefaultMarksPath()}
+
+	if *repos != "" || *profile != "" {
+		if ghErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", ghErr)
+			os.Exit(headless.ExitUsage)
+		}
+		for _, r := range strings.Split(*repos, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				opts.Repos = append(opts.Repos, r)
+			}
+		}
+		name := *profile
+		if name == "" {
+			name = cfg.ProfileFor(opts.Repos...)
+		}
+		if cfg, err = cfg.WithProfile(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(headless.ExitUsage)
+		}
+		if len(opts.Repos) == 0 {
+			// --profile만 지정하면 프로필의 레포 목록을 사용
+			p, _ := cfg.LookupProfile(name)
+			if !p.Concrete() {
+				fmt.Fprintf(os.Stderr, "Error: profile %s has no plain owner/name repos; pass --repo\n", name)
+				os.Exit(headless.ExitUsage)
+			}
+			opts.Repos = p.Repos
+		}
+		if name != "" {
+			fmt.Fprintf(os.Stderr, "Using profile %s\n", name)
+		}
+		// 프로필이 provider를 바꿀 수 있으므로 적용한 뒤에 만든다
+		summarizer, err := llm.New(cfg.LLM)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(headless.ExitUsage)
+		}
+		opts.Days = cfg.Days
+		opts.Branch = cfg.Branch
+		opts.Prompt = cfg.Prompt
+		opts.Workers = cfg.Workers
+		opts.MaxPRs = cfg.MaxPRs
+		opts.TokenBudget = cfg.TokenBudget
+		opts.Limits = cfg.Limits
+		opts.Timeouts = cfg.Timeouts
+		opts.LLMRetry = cfg.LLM.RetryPolicy()
+		opts.History = store
+		opts.Marks = marks
+		opts.GitHub = gh
+		opts.Cache = cache
+		opts.Summarizer = summarizer
+		os.Exit(headless.Run(opts))
+	}
+
+	// 기본 설정의 LLM 오류는 점검 목록에 보이고, 그 설정을 쓰는 실행만 실패한다
+	summarizer, llmErr := llm.New(cfg.LLM)
 	p := tea.NewProgram(
-		app.NewModel(),
+		app.NewModel(app.Options{
+			GitHub:     gh,
+			GitHubErr:  ghErr,
+			Cache:      cache,
+			History:    store,
+			Marks:      marks,
+			Summarizer: summarizer,
+			LLMErr:     llmErr,
+			Config:     cfg,
+		}),
 		tea.WithAltScreen(),
 	)
 